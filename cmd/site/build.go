@@ -0,0 +1,23 @@
+package main
+
+import (
+	"flag"
+
+	"github.com/ericstrs/site/internal/server"
+)
+
+// runBuild exports the site as static files.
+func runBuild(args []string) error {
+	var cf configFlags
+	fs := flag.NewFlagSet("build", flag.ExitOnError)
+	cf.register(fs, false)
+	out := fs.String("o", "dist", "output directory")
+	fs.Parse(args)
+
+	cfg, err := cf.load(fs)
+	if err != nil {
+		return err
+	}
+
+	return server.Build(cfg, *out)
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ericstrs/site/internal/server"
+)
+
+// runCheck validates the config, the templates and every markdown
+// document in the docs directory.
+func runCheck(args []string) error {
+	var cf configFlags
+	fs := flag.NewFlagSet("check", flag.ExitOnError)
+	cf.register(fs, false)
+	templates := fs.String("templates", "", "on-disk template directory to use instead of the embedded templates")
+	fs.Parse(args)
+
+	cfg, err := cf.load(fs)
+	if err != nil {
+		return err
+	}
+
+	errs := server.Check(cfg, *templates)
+	for _, err := range errs {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%d problems found", len(errs))
+	}
+
+	fmt.Println("ok")
+	return nil
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"flag"
+
+	"github.com/ericstrs/site"
+	"github.com/ericstrs/site/internal/config"
+)
+
+// configFlags are the flags shared by commands that load the config.
+// Flags that are set override the values read from the config file.
+type configFlags struct {
+	path string
+	host string
+	port int
+	docs string
+}
+
+// register defines the config flags on fs. The host and port flags
+// are only defined when server is true.
+func (f *configFlags) register(fs *flag.FlagSet, server bool) {
+	fs.StringVar(&f.path, "config", "", "path to the config file")
+	fs.StringVar(&f.docs, "docs", "", "path to the docs directory")
+	if server {
+		fs.StringVar(&f.host, "host", "", "host to listen on")
+		fs.IntVar(&f.port, "port", 0, "port to listen on")
+	}
+}
+
+// load loads the config, applies the flags set on fs and validates
+// the result. A binary built with embedded content uses the embedded
+// config and docs unless the config flag is set.
+func (f *configFlags) load(fs *flag.FlagSet) (*config.Config, error) {
+	var (
+		cfg *config.Config
+		err error
+	)
+	if site.FS != nil && f.path == "" {
+		cfg, err = config.Load(site.FS, "config.yml")
+	} else {
+		cfg, err = config.LoadConfig(f.path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	fs.Visit(func(fl *flag.Flag) {
+		switch fl.Name {
+		case "host":
+			cfg.Host = f.host
+		case "port":
+			cfg.Port = f.port
+		case "docs":
+			cfg.DocsPath = f.docs
+			cfg.DocsFS = nil
+		}
+	})
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ericstrs/site/internal/config"
+)
+
+// TestLoadPrecedence checks that values come from flags, then the
+// environment, then the config file, then the defaults.
+func TestLoadPrecedence(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "docs"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "config.yml")
+	data := "title: file\nurl: https://example.com\nhost: file\nport: 8080\n"
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("SITE_HOST", "env")
+	t.Setenv("SITE_PORT", "9090")
+
+	var cf configFlags
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	cf.register(fs, true)
+	if err := fs.Parse([]string{"--config", path, "--port", "7070"}); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := cf.load(fs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Port != 7070 {
+		t.Errorf("port = %d, want 7070 from the flag", cfg.Port)
+	}
+	if cfg.Host != "env" {
+		t.Errorf("host = %q, want env from the environment", cfg.Host)
+	}
+	if cfg.Title != "file" {
+		t.Errorf("title = %q, want file from the config file", cfg.Title)
+	}
+	if cfg.TOC.MaxLevel != config.DefaultTOCMaxLevel {
+		t.Errorf("toc max level = %d, want the default %d", cfg.TOC.MaxLevel, config.DefaultTOCMaxLevel)
+	}
+}
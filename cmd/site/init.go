@@ -0,0 +1,22 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/ericstrs/site/internal/config"
+)
+
+// runInit scaffolds a new site in a directory.
+func runInit(args []string) error {
+	fs := flag.NewFlagSet("init", flag.ExitOnError)
+	dir := fs.String("dir", ".", "directory to scaffold the site in")
+	fs.Parse(args)
+
+	if err := config.Init(*dir); err != nil {
+		return err
+	}
+
+	fmt.Println("initialized site in", *dir)
+	return nil
+}
@@ -1,5 +1,51 @@
 package main
 
-import "github.com/ericstrs/site/internal/server"
+import (
+	"fmt"
+	"os"
+	"strings"
+)
 
-func main() { server.Serve() }
+const usage = `usage: site <command> [flags]
+
+commands:
+  init   scaffold config.yml and docs/ in the current directory
+  serve  run the web server (default)
+  build  export the site as static files
+  new    create a content directory: site new <section> <slug>
+  check  validate the config and content
+
+Run "site <command> -h" for the flags of a command.
+`
+
+func main() {
+	args := os.Args[1:]
+	cmd := "serve"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		cmd, args = args[0], args[1:]
+	}
+
+	var err error
+	switch cmd {
+	case "init":
+		err = runInit(args)
+	case "serve":
+		err = runServe(args)
+	case "build":
+		err = runBuild(args)
+	case "new":
+		err = runNew(args)
+	case "check":
+		err = runCheck(args)
+	case "help":
+		fmt.Print(usage)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", cmd, usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
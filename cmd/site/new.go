@@ -0,0 +1,87 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ericstrs/site/internal/config"
+)
+
+// slugPattern matches the directory names accepted for new content.
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// runNew scaffolds a content directory with a README.md in a section.
+func runNew(args []string) error {
+	var cf configFlags
+	fs := flag.NewFlagSet("new", flag.ExitOnError)
+	cf.register(fs, false)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: site new [flags] <section> <slug>")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return errors.New("expected a section and a slug")
+	}
+	kind, slug := fs.Arg(0), fs.Arg(1)
+
+	if !slugPattern.MatchString(slug) {
+		return fmt.Errorf("invalid slug %q: use lowercase letters, digits and hyphens", slug)
+	}
+
+	cfg, err := cf.load(fs)
+	if err != nil {
+		return err
+	}
+
+	// Embedded docs are read-only.
+	if cfg.DocsFS != nil {
+		return errors.New("the docs are embedded in the binary: set --docs to create content on disk")
+	}
+
+	sec, ok := findSection(cfg.Sections, kind)
+	if !ok {
+		return fmt.Errorf("unknown section %q", kind)
+	}
+
+	dir := filepath.Join(cfg.DocsPath, sec.Path, slug)
+	if _, err := os.Stat(dir); err == nil {
+		return fmt.Errorf("%s already exists", dir)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create content directory: %w", err)
+	}
+
+	title := strings.ReplaceAll(slug, "-", " ")
+	content := fmt.Sprintf("---\ntitle: %q\ndate: %s\ntags: []\n---\n\n# %s\n",
+		title, time.Now().Format("2006-01-02"), title)
+
+	path := filepath.Join(dir, "README.md")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+
+	fmt.Println("created", path)
+	return nil
+}
+
+// findSection returns the section whose name or path matches kind,
+// accepting the singular form so "site new blog" finds "blogs".
+func findSection(sections []config.Section, kind string) (config.Section, bool) {
+	for _, sec := range sections {
+		for _, name := range []string{strings.ToLower(sec.Name), sec.Path} {
+			if kind == name || kind+"s" == name {
+				return sec, true
+			}
+		}
+	}
+	return config.Section{}, false
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"errors"
+	"flag"
+
+	"github.com/ericstrs/site/internal/config"
+	"github.com/ericstrs/site/internal/server"
+)
+
+// runServe runs the web server.
+func runServe(args []string) error {
+	var cf configFlags
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	cf.register(fs, true)
+	dev := fs.Bool("dev", false, "watch content, config and templates and live reload browsers")
+	drafts := fs.Bool("drafts", false, "serve drafts and scheduled content")
+	templates := fs.String("templates", "", "on-disk template directory to use instead of the embedded templates")
+	fs.Parse(args)
+
+	cfg, err := cf.load(fs)
+	if err != nil {
+		return err
+	}
+
+	// The config file is only watched in dev mode, which needs the
+	// docs on disk.
+	var configPath string
+	if *dev {
+		if cfg.DocsFS != nil {
+			return errors.New("--dev watches files on disk: set --config or --docs to serve the docs from disk")
+		}
+		configPath, err = config.Path(cf.path)
+		if err != nil {
+			return err
+		}
+	}
+
+	server.Serve(cfg, server.Options{
+		Dev:        *dev,
+		Drafts:     *drafts,
+		ConfigPath: configPath,
+		Templates:  *templates,
+		LoadConfig: func() (*config.Config, error) {
+			return cf.load(fs)
+		},
+	})
+	return nil
+}
@@ -0,0 +1,9 @@
+//go:build !embed
+
+package site
+
+import "io/fs"
+
+// FS is nil without the "embed" build tag, and content is read from
+// disk.
+var FS fs.FS
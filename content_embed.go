@@ -0,0 +1,14 @@
+//go:build embed
+
+package site
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed config.yml all:docs
+var files embed.FS
+
+// FS holds the embedded config.yml and docs directory.
+var FS fs.FS = files
@@ -0,0 +1,6 @@
+// Package site holds the content of the site. Built with the "embed"
+// build tag, the docs directory and config file beside this file are
+// embedded into the binary, so it can be deployed as a single file:
+//
+//	go build -tags embed ./cmd/site
+package site
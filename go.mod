@@ -3,6 +3,7 @@ module github.com/ericstrs/site
 go 1.22.4
 
 require (
+	github.com/BurntSushi/toml v1.4.0
 	github.com/alecthomas/chroma/v2 v2.2.0
 	github.com/yuin/goldmark v1.7.4
 	github.com/yuin/goldmark-highlighting/v2 v2.0.0-20230729083705-37449abec8cc
@@ -1,21 +1,32 @@
 package config
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
-	"io/ioutil"
+	"io"
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
 
 	"gopkg.in/yaml.v3"
 )
 
-var ErrConfigNotFound = errors.New("config file not found and SITE_CONFIG environment variable is not set")
+// ErrConfigNotFound is returned when there is no config file to load.
+var ErrConfigNotFound = errors.New("config file not found")
+
+// Default syntax highlighting themes, used when the config file sets
+// none.
+const (
+	DefaultLightTheme = "github"
+	DefaultDarkTheme  = "monokai"
+)
 
 // Config represents the layout of the configuration file.
 type Config struct {
 	Title       string             `yaml:"title"`
-	URL         string             `yaml:url`
+	URL         string             `yaml:"url"`
 	Host        string             `yaml:"host"`
 	Port        int                `yaml:"port"`
 	Description string             `yaml:"description"`
@@ -24,6 +35,20 @@ type Config struct {
 	Nav         []NavItem          `yaml:"nav"`
 	Social      []NavItem          `yaml:"social"`
 	DocsPath    string             `yaml:"docs_path"`
+	ThemesPath  string             `yaml:"themes_path"`
+	Sections    []Section          `yaml:"sections,omitempty"`
+	Robots      []RobotsRule       `yaml:"robots,omitempty"`
+	TOC         TOC                `yaml:"toc,omitempty"`
+
+	// DocsFS, when set, is the docs directory in place of DocsPath,
+	// such as content embedded into the binary.
+	DocsFS fs.FS `yaml:"-"`
+
+	// source and node locate the values of the config file in error
+	// messages, env holds the key paths set by environment variables.
+	source string
+	node   *yaml.Node
+	env    map[string]bool
 }
 
 // SyntaxHighlighting contains settings for syntax highlighting themes.
@@ -37,67 +62,199 @@ type ThemeConfig struct {
 	Theme string `yaml:"theme"`
 }
 
+// TOC selects the heading levels listed in the table of contents of
+// documents.
+type TOC struct {
+	MinLevel int `yaml:"min_level"`
+	MaxLevel int `yaml:"max_level"`
+}
+
+// Default table of contents heading levels.
+const (
+	DefaultTOCMinLevel = 2
+	DefaultTOCMaxLevel = 3
+)
+
 // NavItem represents a navigation item.
 type NavItem struct {
 	Name string `yaml:"name"`
 	URL  string `yaml:"url"`
 }
 
-// LoadConfig loads or initializes the config file and ensures
-// the "docs" directory exists. It returns a pointer to the Config
-// struct and any error encountered during the process.
-func LoadConfig() (*Config, error) {
-	cfg, err := loadFile()
-	if err != nil {
-		if errors.Is(err, ErrConfigNotFound) {
-			path := "config.yml" // Default to "config.yml" in the current directory
-			if err := createConfigFile(path); err != nil {
-				return nil, fmt.Errorf("failed to create config file: %w", err)
-			}
-			cfg, err = loadFile()
-			if err != nil {
-				return nil, err
-			}
-		} else {
+// RobotsRule is a group of robots.txt rules for the crawlers matching
+// UserAgent. Without rules every crawler may visit every page.
+type RobotsRule struct {
+	UserAgent string   `yaml:"user_agent"`
+	Allow     []string `yaml:"allow"`
+	Disallow  []string `yaml:"disallow"`
+}
+
+// LoadConfig loads the config file at path. An empty path searches the
+// current directory and then SITE_CONFIG. Relative paths in the config
+// are resolved from the directory of the file. Without docs_path, the
+// docs directory beside the config file is used, falling back to the
+// docs directory in the working directory and then to DOCS_DIR.
+// LoadConfig never writes files: a missing config file is an error
+// wrapping ErrConfigNotFound, and Init scaffolds a new site.
+func LoadConfig(path string) (*Config, error) {
+	if path == "" {
+		var err error
+		path, err = findConfigDir()
+		if err != nil {
 			return nil, err
 		}
 	}
 
+	dir := filepath.Dir(path)
+	cfg, err := load(os.DirFS(dir), filepath.Base(path), dir)
+	if err != nil {
+		return nil, err
+	}
+
 	if cfg.DocsPath == "" {
-		docsPath, err := findDocsDir()
-		if err != nil {
-			docsPath = "docs" // Default to "docs" in current directory
-			if err := os.MkdirAll(docsPath, 0755); err != nil {
-				return nil, fmt.Errorf("failed to resolve docs path and failed to create docs directory: %w", err)
-			}
+		if docs, err := findDocsDir(); err == nil {
+			cfg.DocsPath = docs
+		}
+	}
 
-			readmePath := filepath.Join(docsPath, "README.md")
-			if _, err := os.Create(readmePath); err != nil {
-				return nil, fmt.Errorf("failed to create README.md: %w", err)
-			}
+	return cfg, nil
+}
+
+// Load loads the config file name of fsys. The docs directory is read
+// from fsys too: DocsFS is the docs_path directory, relative to the
+// directory of name, or without docs_path the docs directory beside
+// the config file when fsys has one. An absolute docs_path, or one set
+// with SITE_DOCS_PATH, stays on disk. Load never writes files.
+func Load(fsys fs.FS, name string) (*Config, error) {
+	return load(fsys, name, "")
+}
+
+// Docs returns the docs directory as a file system, the root content
+// is loaded from.
+func (c *Config) Docs() fs.FS {
+	if c.DocsFS != nil {
+		return c.DocsFS
+	}
+	return os.DirFS(c.DocsPath)
+}
+
+// Path returns the path of the config file LoadConfig reads for the
+// given path.
+func Path(path string) (string, error) {
+	if path != "" {
+		return path, nil
+	}
+	return findConfigDir()
+}
+
+// load loads the config file name of fsys, which is rooted at the
+// directory dir on disk, or is not on disk when dir is empty. Relative
+// paths in the config are resolved from the directory of name and
+// joined to dir. Without dir, the docs directory is a sub tree of
+// fsys.
+func load(fsys fs.FS, name, dir string) (*Config, error) {
+	source := filepath.Join(dir, filepath.FromSlash(name))
+	data, err := fs.ReadFile(fsys, name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrConfigNotFound, source)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	cfg, err := Parse(data, source)
+	if err != nil {
+		return nil, err
+	}
+
+	// Relative paths are relative to the directory of the config file,
+	// so the site can be served from any working directory.
+	base := path.Dir(name)
+	resolve := func(p string) string {
+		if filepath.IsAbs(p) {
+			return p
 		}
+		return filepath.Join(dir, filepath.FromSlash(path.Join(base, filepath.ToSlash(p))))
+	}
+	// Paths from the environment stay relative to the working
+	// directory.
+	if !cfg.env["themes_path"] {
+		cfg.ThemesPath = resolve(cfg.ThemesPath)
+	}
 
-		cfg.DocsPath = docsPath
+	switch {
+	case dir == "" && !cfg.env["docs_path"] && !filepath.IsAbs(cfg.DocsPath):
+		// Without a directory on disk, the docs are read from fsys
+		// unless the environment points elsewhere.
+		docs := path.Join(base, filepath.ToSlash(cfg.DocsPath))
+		if cfg.DocsPath == "" {
+			docs = path.Join(base, "docs")
+		}
+		if info, err := fs.Stat(fsys, docs); err == nil && info.IsDir() {
+			if cfg.DocsFS, err = fs.Sub(fsys, docs); err != nil {
+				return nil, err
+			}
+			cfg.DocsPath = ""
+		} else if cfg.DocsPath != "" {
+			return nil, cfg.errorAt(fmt.Sprintf("docs directory %s not found", docs), "docs_path")
+		}
+	case cfg.DocsPath != "":
+		if !cfg.env["docs_path"] {
+			cfg.DocsPath = resolve(cfg.DocsPath)
+		}
+	default:
+		if info, err := fs.Stat(fsys, path.Join(base, "docs")); err == nil && info.IsDir() {
+			cfg.DocsPath = resolve("docs")
+		}
 	}
 
 	return cfg, nil
 }
 
-// loadFile attempts to read and unmarshal the configuration file.
-func loadFile() (*Config, error) {
-	path, err := findConfigDir()
-	if err != nil {
+// Parse parses the content of the config file named source in error
+// messages, overrides it with the environment and applies the
+// defaults. Keys that are not config fields are errors. Relative paths
+// are kept relative to the working directory. The values are not
+// checked until Validate is called.
+func Parse(data []byte, source string) (*Config, error) {
+	cfg := Config{source: source}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, fmt.Errorf("%s: failed to unmarshal yaml: %w", source, err)
+	}
+	cfg.node = &node
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, cfg.decodeError(err)
+	}
+
+	if err := cfg.applyEnv(); err != nil {
 		return nil, err
 	}
 
-	data, err := ioutil.ReadFile(path)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read config file: %w", err)
+	if cfg.ThemesPath == "" {
+		cfg.ThemesPath = "themes"
 	}
 
-	var cfg Config
-	if err := yaml.Unmarshal(data, &cfg); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal yaml: %w", err)
+	if cfg.Syntax.LightMode.Theme == "" {
+		cfg.Syntax.LightMode.Theme = DefaultLightTheme
+	}
+	if cfg.Syntax.DarkMode.Theme == "" {
+		cfg.Syntax.DarkMode.Theme = DefaultDarkTheme
+	}
+
+	if cfg.TOC.MinLevel == 0 {
+		cfg.TOC.MinLevel = DefaultTOCMinLevel
+	}
+	if cfg.TOC.MaxLevel == 0 {
+		cfg.TOC.MaxLevel = DefaultTOCMaxLevel
+	}
+
+	if err := cfg.normalizeSections(); err != nil {
+		return nil, cfg.errorAt(err.Error(), "sections")
 	}
 
 	return &cfg, nil
@@ -111,12 +268,45 @@ func findConfigDir() (string, error) {
 	if os.IsNotExist(err) {
 		path = os.Getenv("SITE_CONFIG")
 		if path == "" {
-			return "", ErrConfigNotFound
+			return "", fmt.Errorf(`%w: no config.yml in the current directory and SITE_CONFIG is not set; run "site init" to create one`, ErrConfigNotFound)
 		}
 	}
 	return path, nil
 }
 
+// Init scaffolds a new site in dir: a default config.yml and a docs
+// directory with the home, about, notes and blogs pages. Existing
+// files are left untouched.
+func Init(dir string) error {
+	path := filepath.Join(dir, "config.yml")
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		if err := createConfigFile(path); err != nil {
+			return fmt.Errorf("failed to create config file: %w", err)
+		}
+	}
+
+	docs := map[string]string{
+		"README.md":       "# Home\n",
+		"about.md":        "# About\n",
+		"notes/README.md": "# Notes\n",
+		"blogs/README.md": "# Blog\n",
+	}
+	for name, content := range docs {
+		path := filepath.Join(dir, "docs", filepath.FromSlash(name))
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("failed to create docs directory: %w", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return fmt.Errorf("failed to create %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
 // createConfigFile creates a default configuration file.
 func createConfigFile(path string) error {
 	defaultConfig := &Config{
@@ -127,8 +317,8 @@ func createConfigFile(path string) error {
 		Description: "",
 		Theme:       "default",
 		Syntax: SyntaxHighlighting{
-			DarkMode:  ThemeConfig{Theme: "monokai"},
-			LightMode: ThemeConfig{Theme: "github"},
+			DarkMode:  ThemeConfig{Theme: DefaultDarkTheme},
+			LightMode: ThemeConfig{Theme: DefaultLightTheme},
 		},
 		Nav:    []NavItem{},
 		Social: []NavItem{},
@@ -139,13 +329,19 @@ func createConfigFile(path string) error {
 		return fmt.Errorf("failed to marshal default config: %w", err)
 	}
 
-	if err := ioutil.WriteFile(path, content, 0644); err != nil {
+	if err := os.WriteFile(path, content, 0644); err != nil {
 		return fmt.Errorf("failed to write default config file: %w", err)
 	}
 
 	return nil
 }
 
+// isDir reports whether path is an existing directory.
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
 // findDocsDir attempts to locate the "docs" directory in the current directory.
 // If the "docs" directory does not exist, it falls back on DOCS_DIR to
 // retrieve the path to the docs directory.
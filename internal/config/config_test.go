@@ -0,0 +1,161 @@
+package config
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+const testConfig = "title: t\nurl: http://localhost:8080\nport: 8080\n"
+
+func TestLoadNotFound(t *testing.T) {
+	_, err := Load(fstest.MapFS{}, "config.yml")
+	if !errors.Is(err, ErrConfigNotFound) {
+		t.Fatalf("Load() error = %v, want ErrConfigNotFound", err)
+	}
+}
+
+func TestLoadDocs(t *testing.T) {
+	tests := []struct {
+		name     string
+		fsys     fstest.MapFS
+		config   string
+		docsPath string
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:   "beside config",
+			fsys:   fstest.MapFS{"site/docs/README.md": {Data: []byte("# Home\n")}},
+			config: "site/config.yml",
+			want:   "# Home\n",
+		},
+		{
+			name:     "docs_path",
+			fsys:     fstest.MapFS{"site/content/README.md": {Data: []byte("# Content\n")}},
+			config:   "site/config.yml",
+			docsPath: "content",
+			want:     "# Content\n",
+		},
+		{
+			name:     "missing docs_path",
+			fsys:     fstest.MapFS{},
+			config:   "site/config.yml",
+			docsPath: "content",
+			wantErr:  true,
+		},
+		{
+			name:   "no docs",
+			fsys:   fstest.MapFS{},
+			config: "config.yml",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := testConfig
+			if tt.docsPath != "" {
+				data += "docs_path: " + tt.docsPath + "\n"
+			}
+			tt.fsys[tt.config] = &fstest.MapFile{Data: []byte(data)}
+
+			cfg, err := Load(tt.fsys, tt.config)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Load() succeeded, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.want == "" {
+				if cfg.DocsFS != nil {
+					t.Fatal("DocsFS is set without a docs directory")
+				}
+				if err := cfg.Validate(); err == nil {
+					t.Fatal("Validate() succeeded without a docs directory")
+				}
+				return
+			}
+			if cfg.DocsFS == nil {
+				t.Fatal("DocsFS is nil")
+			}
+			if err := cfg.Validate(); err != nil {
+				t.Fatal(err)
+			}
+			got, err := fs.ReadFile(cfg.Docs(), "README.md")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("README.md = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadDocsFromEnv(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(EnvPrefix+"DOCS_PATH", dir)
+	fsys := fstest.MapFS{
+		"config.yml":     {Data: []byte(testConfig)},
+		"docs/README.md": {Data: []byte("# Home\n")},
+	}
+
+	cfg, err := Load(fsys, "config.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.DocsFS != nil || cfg.DocsPath != dir {
+		t.Fatalf("docs = %v, %q, want %q on disk", cfg.DocsFS, cfg.DocsPath, dir)
+	}
+}
+
+func TestLoadConfigReadOnly(t *testing.T) {
+	t.Setenv("SITE_CONFIG", "")
+	t.Setenv("DOCS_DIR", "")
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yml")
+
+	if _, err := LoadConfig(path); !errors.Is(err, ErrConfigNotFound) {
+		t.Fatalf("LoadConfig() error = %v, want ErrConfigNotFound", err)
+	}
+	assertFiles(t, dir)
+
+	if err := os.WriteFile(path, []byte(testConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("Validate() succeeded without a docs directory")
+	}
+	assertFiles(t, dir, "config.yml")
+}
+
+// assertFiles fails the test unless dir holds exactly the named files.
+func assertFiles(t *testing.T, dir string, names ...string) {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, e := range entries {
+		got = append(got, e.Name())
+	}
+	if len(got) != len(names) {
+		t.Fatalf("%s holds %v, want %v", dir, got, names)
+	}
+	for i := range got {
+		if got[i] != names[i] {
+			t.Fatalf("%s holds %v, want %v", dir, got, names)
+		}
+	}
+}
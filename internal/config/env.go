@@ -0,0 +1,101 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix starts the name of the environment variables overriding
+// config fields. The rest of the name is the upper case key path of
+// the field joined by underscores, such as SITE_PORT or
+// SITE_SYNTAX_HIGHLIGHTING_DARK_MODE_THEME. Lists, such as SITE_NAV,
+// are given as YAML.
+const EnvPrefix = "SITE_"
+
+// applyEnv overrides the fields of c with the environment variables
+// that are set, and records the keys they override.
+func (c *Config) applyEnv() error {
+	c.env = make(map[string]bool)
+	return c.applyEnvTo(reflect.ValueOf(c).Elem(), nil)
+}
+
+// applyEnvTo overrides the fields of the struct v, found at the given
+// key path, with the environment.
+func (c *Config) applyEnvTo(v reflect.Value, path []string) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		key, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if !field.IsExported() || key == "" || key == "-" {
+			continue
+		}
+		keyPath := append(path[:len(path):len(path)], key)
+
+		if field.Type.Kind() == reflect.Struct {
+			if err := c.applyEnvTo(v.Field(i), keyPath); err != nil {
+				return err
+			}
+			continue
+		}
+
+		name := EnvPrefix + strings.ToUpper(strings.Join(keyPath, "_"))
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+		if err := setField(v.Field(i), value); err != nil {
+			return fmt.Errorf("%s: invalid value %q: %w", name, value, err)
+		}
+		c.env[strings.Join(keyPath, ".")] = true
+	}
+	return nil
+}
+
+// setField sets the field v from the environment variable value.
+func setField(v reflect.Value, value string) error {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(n))
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	default:
+		ptr := reflect.New(v.Type())
+		if err := yaml.Unmarshal([]byte(value), ptr.Interface()); err != nil {
+			return err
+		}
+		v.Set(ptr.Elem())
+	}
+	return nil
+}
+
+// envName returns the environment variable that set the value at the
+// given key path, or its closest parent, and whether there is one.
+func (c *Config) envName(path ...any) (string, bool) {
+	var keys []string
+	for _, key := range path {
+		s, ok := key.(string)
+		if !ok {
+			continue
+		}
+		keys = append(keys, s)
+		if c.env[strings.Join(keys, ".")] {
+			return EnvPrefix + strings.ToUpper(strings.Join(keys, "_")), true
+		}
+	}
+	return "", false
+}
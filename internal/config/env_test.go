@@ -0,0 +1,70 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestEnv(t *testing.T) {
+	t.Setenv("SITE_PORT", "9090")
+	t.Setenv("SITE_SYNTAX_HIGHLIGHTING_DARK_MODE_THEME", "dracula")
+	t.Setenv("SITE_NAV", "[{name: blog, url: /blogs}, {name: notes, url: /notes}]")
+
+	data := "title: file\nport: 8080\nnav:\n  - name: about\n    url: /about\n"
+	cfg, err := Parse([]byte(data), "config.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Port != 9090 {
+		t.Errorf("port = %d, want 9090 from the environment", cfg.Port)
+	}
+	if cfg.Syntax.DarkMode.Theme != "dracula" {
+		t.Errorf("dark mode theme = %q, want dracula from the environment", cfg.Syntax.DarkMode.Theme)
+	}
+	wantNav := []NavItem{{Name: "blog", URL: "/blogs"}, {Name: "notes", URL: "/notes"}}
+	if !reflect.DeepEqual(cfg.Nav, wantNav) {
+		t.Errorf("nav = %+v, want %+v", cfg.Nav, wantNav)
+	}
+	if cfg.Title != "file" {
+		t.Errorf("title = %q, want file from the config file", cfg.Title)
+	}
+	if cfg.Syntax.LightMode.Theme != DefaultLightTheme {
+		t.Errorf("light mode theme = %q, want the default %q", cfg.Syntax.LightMode.Theme, DefaultLightTheme)
+	}
+}
+
+func TestEnvInvalid(t *testing.T) {
+	tests := []struct {
+		name, value, want string
+	}{
+		{"SITE_PORT", "eighty", `SITE_PORT: invalid value "eighty"`},
+		{"SITE_NAV", "[{name: blog", `SITE_NAV: invalid value "[{name: blog"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(tt.name, tt.value)
+			_, err := Parse([]byte("title: t\n"), "config.yml")
+			if err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+				t.Errorf("Parse() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnvValidate(t *testing.T) {
+	t.Setenv("SITE_PORT", "0")
+
+	cfg, err := Parse([]byte("url: https://example.com\nport: 8080\n"), "config.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.DocsFS = fstest.MapFS{}
+
+	want := "SITE_PORT: port 0 is not between 1 and 65535"
+	if err := cfg.Validate(); err == nil || err.Error() != want {
+		t.Errorf("Validate() = %v, want %q", err, want)
+	}
+}
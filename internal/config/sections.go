@@ -0,0 +1,111 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Section is a directory of content beneath the docs directory that
+// gets a listing page and one page per document.
+type Section struct {
+	// Name identifies the section and is shown in listings and feeds.
+	Name string `yaml:"name"`
+
+	// Path is the directory beneath the docs directory holding one
+	// directory per document. Defaults to the lower case name.
+	Path string `yaml:"path"`
+
+	// URL is the prefix the section is served under. Defaults to
+	// "/" followed by the path.
+	URL string `yaml:"url"`
+
+	// Template renders each document. Defaults to "note".
+	Template string `yaml:"template"`
+
+	// ListTemplate renders the listing. Defaults to "list".
+	ListTemplate string `yaml:"list_template"`
+
+	// Sort orders the listing by "id", "title" or publish "date".
+	// Defaults to "date".
+	Sort string `yaml:"sort"`
+
+	// Order is "asc" or "desc". Defaults to "desc", newest first.
+	Order string `yaml:"order"`
+
+	// PageSize is the number of documents per listing page. Zero lists
+	// every document on one page.
+	PageSize int `yaml:"page_size"`
+}
+
+// defaultSections are used when the config file declares none.
+var defaultSections = []Section{
+	{Name: "Blogs", Path: "blogs", Template: "blog"},
+	{Name: "Notes", Path: "notes"},
+}
+
+// normalizeSections fills in the section defaults and reports
+// sections that would collide with one another.
+func (c *Config) normalizeSections() error {
+	if len(c.Sections) == 0 {
+		c.Sections = append([]Section(nil), defaultSections...)
+	}
+
+	names := make(map[string]bool)
+	urls := make(map[string]bool)
+	for i := range c.Sections {
+		s := &c.Sections[i]
+		if s.Name == "" {
+			return fmt.Errorf("section %d: missing name", i+1)
+		}
+		if s.Path == "" {
+			s.Path = strings.ToLower(s.Name)
+		}
+		if s.URL == "" {
+			s.URL = "/" + s.Path
+		}
+		s.URL = "/" + strings.Trim(s.URL, "/")
+		if s.Template == "" {
+			s.Template = "note"
+		}
+		if s.ListTemplate == "" {
+			s.ListTemplate = "list"
+		}
+		if s.Sort == "" {
+			s.Sort = "date"
+		}
+		if s.Order == "" {
+			s.Order = "desc"
+		}
+
+		switch {
+		case strings.Contains(s.Path, "/") || s.Path == "." || s.Path == "..":
+			return fmt.Errorf("section %q: path must be a directory directly beneath the docs directory", s.Name)
+		case s.URL == "/":
+			return fmt.Errorf("section %q: url must not be the site root", s.Name)
+		case s.Sort != "id" && s.Sort != "title" && s.Sort != "date":
+			return fmt.Errorf("section %q: sort must be id, title or date", s.Name)
+		case s.Order != "asc" && s.Order != "desc":
+			return fmt.Errorf("section %q: order must be asc or desc", s.Name)
+		case s.PageSize < 0:
+			return fmt.Errorf("section %q: page_size must not be negative", s.Name)
+		case names[s.Name]:
+			return fmt.Errorf("section %q: duplicate name", s.Name)
+		case urls[s.URL]:
+			return fmt.Errorf("section %q: duplicate url %q", s.Name, s.URL)
+		}
+		names[s.Name] = true
+		urls[s.URL] = true
+	}
+
+	// The routes of a section nested under another would conflict
+	// with the document and page routes of the outer one.
+	for _, s := range c.Sections {
+		for _, outer := range c.Sections {
+			if strings.HasPrefix(s.URL, outer.URL+"/") {
+				return fmt.Errorf("section %q: url %q is nested under section %q", s.Name, s.URL, outer.Name)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,27 @@
+package config
+
+import "testing"
+
+func TestNormalizeSections(t *testing.T) {
+	tests := []struct {
+		name     string
+		sections []Section
+		wantErr  bool
+	}{
+		{"defaults", nil, false},
+		{"sibling urls", []Section{{Name: "Blogs"}, {Name: "Blog archive", URL: "/blogs-archive"}}, false},
+		{"duplicate url", []Section{{Name: "Blogs"}, {Name: "Posts", URL: "/blogs"}}, true},
+		{"nested url", []Section{{Name: "Blogs"}, {Name: "Archive", URL: "/blogs/page"}}, true},
+		{"nested outer last", []Section{{Name: "Archive", URL: "/blogs/archive"}, {Name: "Blogs"}}, true},
+		{"site root", []Section{{Name: "Blogs", URL: "/"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Config{Sections: tt.sections}
+			err := c.normalizeSections()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("normalizeSections() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
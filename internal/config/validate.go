@@ -0,0 +1,180 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2/styles"
+	"gopkg.in/yaml.v3"
+)
+
+// Validate reports every problem of the config, each prefixed with the
+// file and line it comes from when known.
+func (c *Config) Validate() error {
+	var errs []error
+	add := func(msg string, path ...any) {
+		errs = append(errs, c.errorAt(msg, path...))
+	}
+
+	if c.Port < 1 || c.Port > 65535 {
+		add(fmt.Sprintf("port %d is not between 1 and 65535", c.Port), "port")
+	}
+
+	if u, err := url.Parse(c.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		add(fmt.Sprintf("url %q must be an absolute http or https URL", c.URL), "url")
+	}
+
+	menus := []struct {
+		key   string
+		items []NavItem
+	}{
+		{"nav", c.Nav},
+		{"social", c.Social},
+	}
+	for _, menu := range menus {
+		for i, item := range menu.items {
+			if item.Name == "" {
+				add(fmt.Sprintf("%s item %d has no name", menu.key, i+1), menu.key, i)
+			}
+			if !validLink(item.URL) {
+				add(fmt.Sprintf("%s item %q: url %q must be a path starting with / or an absolute URL", menu.key, item.Name, item.URL), menu.key, i, "url")
+			}
+		}
+	}
+
+	if c.DocsFS == nil && !isDir(c.DocsPath) {
+		if c.DocsPath == "" {
+			add(`docs directory not found: set docs_path or DOCS_DIR, or run "site init"`, "docs_path")
+		} else {
+			add(fmt.Sprintf("docs directory %s not found", c.DocsPath), "docs_path")
+		}
+	}
+
+	if c.Theme != "" && c.Theme != "default" && !isDir(filepath.Join(c.ThemesPath, c.Theme)) {
+		add(fmt.Sprintf("theme %q not found in %s", c.Theme, c.ThemesPath), "theme")
+	}
+
+	if c.TOC.MinLevel < 1 || c.TOC.MaxLevel > 6 || c.TOC.MinLevel > c.TOC.MaxLevel {
+		add(fmt.Sprintf("toc min_level %d and max_level %d must satisfy 1 <= min_level <= max_level <= 6", c.TOC.MinLevel, c.TOC.MaxLevel), "toc")
+	}
+
+	modes := []struct {
+		key   string
+		theme string
+	}{
+		{"light_mode", c.Syntax.LightMode.Theme},
+		{"dark_mode", c.Syntax.DarkMode.Theme},
+	}
+	for _, mode := range modes {
+		if _, ok := styles.Registry[mode.theme]; !ok {
+			add(fmt.Sprintf("unknown syntax highlighting theme %q", mode.theme), "syntax_highlighting", mode.key, "theme")
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// typeErrorPattern matches an error of yaml.TypeError.
+var typeErrorPattern = regexp.MustCompile(`^line (\d+): (.*)$`)
+
+// decodeError returns the errors of decoding the config file, one per
+// line, each located in the config file.
+func (c *Config) decodeError(err error) error {
+	var typeErr *yaml.TypeError
+	if !errors.As(err, &typeErr) {
+		return fmt.Errorf("%s: failed to unmarshal yaml: %w", c.source, err)
+	}
+
+	var errs []error
+	for _, msg := range typeErr.Errors {
+		m := typeErrorPattern.FindStringSubmatch(msg)
+		if m == nil {
+			errs = append(errs, fmt.Errorf("%s: %s", c.source, msg))
+			continue
+		}
+		msg = m[2]
+		if field, _, ok := strings.Cut(strings.TrimPrefix(msg, "field "), " not found in type"); ok {
+			msg = fmt.Sprintf("unknown key %q", field)
+		}
+		errs = append(errs, fmt.Errorf("%s:%s: %s", c.source, m[1], msg))
+	}
+	return errors.Join(errs...)
+}
+
+// validLink reports whether s is a site path or an absolute URL.
+func validLink(s string) bool {
+	if strings.HasPrefix(s, "/") {
+		return true
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return false
+	}
+	switch u.Scheme {
+	case "http", "https":
+		return u.Host != ""
+	case "mailto":
+		return u.Opaque != ""
+	}
+	return false
+}
+
+// errorAt returns an error with msg located at the given key path of
+// the config file, made of mapping keys and sequence indexes.
+func (c *Config) errorAt(msg string, path ...any) error {
+	if name, ok := c.envName(path...); ok {
+		return fmt.Errorf("%s: %s", name, msg)
+	}
+
+	source := c.source
+	if source == "" {
+		source = "config"
+	}
+	if line := lineOf(c.node, path...); line > 0 {
+		return fmt.Errorf("%s:%d: %s", source, line, msg)
+	}
+	return fmt.Errorf("%s: %s", source, msg)
+}
+
+// lineOf returns the line of the value at the given key path of a
+// parsed YAML document, or of its closest parent present in the
+// document. It returns zero for a nil node.
+func lineOf(node *yaml.Node, path ...any) int {
+	if node == nil {
+		return 0
+	}
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+
+	line := 0
+	for _, key := range path {
+		var next *yaml.Node
+		switch key := key.(type) {
+		case string:
+			if node.Kind == yaml.MappingNode {
+				for i := 0; i+1 < len(node.Content); i += 2 {
+					if node.Content[i].Value == key {
+						line = node.Content[i].Line
+						next = node.Content[i+1]
+						break
+					}
+				}
+			}
+		case int:
+			if node.Kind == yaml.SequenceNode && key < len(node.Content) {
+				next = node.Content[key]
+				line = next.Line
+			}
+		}
+		if next == nil {
+			break
+		}
+		node = next
+	}
+	return line
+}
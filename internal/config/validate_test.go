@@ -0,0 +1,112 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{
+			name: "unknown key",
+			data: "title: t\ntitel: t\n",
+			want: `config.yml:2: unknown key "titel"`,
+		},
+		{
+			name: "nested unknown key",
+			data: "title: t\ntoc:\n  min_level: 2\n  max: 3\n",
+			want: `config.yml:4: unknown key "max"`,
+		},
+		{
+			name: "several unknown keys",
+			data: "titel: t\nprot: 80\n",
+			want: "config.yml:1: unknown key \"titel\"\nconfig.yml:2: unknown key \"prot\"",
+		},
+		{
+			name: "wrong type",
+			data: "title: t\nport: eighty\n",
+			want: "config.yml:2: cannot unmarshal !!str `eighty` into int",
+		},
+		{
+			name: "syntax",
+			data: "title: [t\n",
+			want: "config.yml: failed to unmarshal yaml",
+		},
+		{
+			name: "section",
+			data: "sections:\n  - path: blogs\n",
+			want: "config.yml:1: section 1: missing name",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.data), "config.yml")
+			if err == nil {
+				t.Fatal("Parse() succeeded, want error")
+			}
+			if !strings.HasPrefix(err.Error(), tt.want) {
+				t.Errorf("Parse() error = %q, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	data := `title: t
+url: example.com
+port: 70000
+nav:
+  - name: blog
+    url: /blogs
+  - url: blogs
+social:
+  - name: mail
+    url: mailto:me@example.com
+theme: missing
+toc:
+  min_level: 4
+  max_level: 3
+syntax_highlighting:
+  dark_mode:
+    theme: nope
+`
+	cfg, err := Parse([]byte(data), "config.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.DocsFS = fstest.MapFS{}
+	cfg.ThemesPath = t.TempDir()
+
+	want := []string{
+		"config.yml:3: port 70000 is not between 1 and 65535",
+		`config.yml:2: url "example.com" must be an absolute http or https URL`,
+		"config.yml:7: nav item 2 has no name",
+		`config.yml:7: nav item "": url "blogs" must be a path starting with / or an absolute URL`,
+		`config.yml:11: theme "missing" not found in ` + cfg.ThemesPath,
+		"config.yml:12: toc min_level 4 and max_level 3 must satisfy 1 <= min_level <= max_level <= 6",
+		`config.yml:17: unknown syntax highlighting theme "nope"`,
+	}
+	err = cfg.Validate()
+	if err == nil {
+		t.Fatal("Validate() succeeded, want errors")
+	}
+	if got := strings.Split(err.Error(), "\n"); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Validate() =\n%s\nwant\n%s", err, strings.Join(want, "\n"))
+	}
+}
+
+func TestValidateDefaults(t *testing.T) {
+	cfg, err := Parse([]byte("url: https://example.com\nport: 8080\n"), "config.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.DocsFS = fstest.MapFS{}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+}
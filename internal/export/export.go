@@ -0,0 +1,96 @@
+package export
+
+import (
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// NotFoundPath is requested to capture the body of the not found page.
+// It must not be routed by the exported handler.
+const NotFoundPath = "/404"
+
+// Write requests every page and file path from h and writes the
+// responses as a static tree rooted at dir. Page paths are written as
+// index.html files inside a directory of the same name unless they
+// have a file extension, file paths are always written as is, and the
+// not found page is written to 404.html.
+func Write(h http.Handler, pages, files []string, dir string) error {
+	for _, p := range pages {
+		if err := writePath(h, p, filePath(p), http.StatusOK, dir); err != nil {
+			return err
+		}
+	}
+	for _, p := range files {
+		if err := writePath(h, p, strings.TrimPrefix(path.Clean("/"+p), "/"), http.StatusOK, dir); err != nil {
+			return err
+		}
+	}
+	return writePath(h, NotFoundPath, "404.html", http.StatusNotFound, dir)
+}
+
+// Assets returns the request path of every file in fsys, skipping the
+// given directories.
+func Assets(fsys fs.FS, skip ...string) ([]string, error) {
+	var paths []string
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			for _, s := range skip {
+				if p == s {
+					return fs.SkipDir
+				}
+			}
+			return nil
+		}
+		paths = append(paths, "/"+p)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return paths, nil
+}
+
+// writePath requests p from h and writes the body to name beneath dir
+// when the response has the wanted status.
+func writePath(h http.Handler, p, name string, want int, dir string) error {
+	// The path is escaped, as it would be on the wire.
+	req := httptest.NewRequest(http.MethodGet, (&url.URL{Path: p}).RequestURI(), nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != want {
+		return fmt.Errorf("failed to export %s: unexpected status %d", p, rec.Code)
+	}
+
+	dst := filepath.Join(dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", p, err)
+	}
+	if err := os.WriteFile(dst, rec.Body.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", p, err)
+	}
+	return nil
+}
+
+// filePath returns the file name, relative to the output directory,
+// that the given request path is written to.
+func filePath(p string) string {
+	p = strings.TrimPrefix(path.Clean("/"+p), "/")
+	if p == "" {
+		return "index.html"
+	}
+	if path.Ext(p) != "" {
+		return p
+	}
+	return path.Join(p, "index.html")
+}
@@ -0,0 +1,206 @@
+package feed
+
+import (
+	"bytes"
+	"encoding/xml"
+	"time"
+)
+
+// Feed describes a syndication feed independent of its format.
+type Feed struct {
+	Title       string
+	Description string
+	Link        string // URL of the page the feed belongs to
+	Self        string // URL the feed is served at
+	Author      string
+	Items       []Item
+}
+
+// Item is a single entry of a feed.
+type Item struct {
+	Title     string
+	Link      string
+	Author    string
+	Summary   string
+	Content   string // rendered HTML body
+	Published time.Time
+	Updated   time.Time
+}
+
+type rss struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Atom    string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	AtomLink      rssLink   `xml:"atom:link"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate,omitempty"`
+	Description string  `xml:"description"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// RSS returns the feed encoded as RSS 2.0.
+func RSS(f Feed) ([]byte, error) {
+	ch := rssChannel{
+		Title:       f.Title,
+		Link:        f.Link,
+		Description: f.Description,
+		AtomLink:    rssLink{Href: f.Self, Rel: "self", Type: "application/rss+xml"},
+	}
+	if updated := f.updated(); !updated.IsZero() {
+		ch.LastBuildDate = updated.Format(time.RFC1123Z)
+	}
+
+	for _, it := range f.Items {
+		item := rssItem{
+			Title:       it.Title,
+			Link:        it.Link,
+			GUID:        rssGUID{IsPermaLink: true, Value: it.Link},
+			Description: it.Content,
+		}
+		if !it.Published.IsZero() {
+			item.PubDate = it.Published.Format(time.RFC1123Z)
+		}
+		ch.Items = append(ch.Items, item)
+	}
+
+	return encode(rss{Version: "2.0", Atom: "http://www.w3.org/2005/Atom", Channel: ch})
+}
+
+type atomFeed struct {
+	XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	ID       string      `xml:"id"`
+	Updated  string      `xml:"updated"`
+	Links    []atomLink  `xml:"link"`
+	Author   *atomAuthor `xml:"author,omitempty"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomText struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type atomEntry struct {
+	Title     string      `xml:"title"`
+	ID        string      `xml:"id"`
+	Link      atomLink    `xml:"link"`
+	Published string      `xml:"published,omitempty"`
+	Updated   string      `xml:"updated"`
+	Author    *atomAuthor `xml:"author,omitempty"`
+	Summary   *atomText   `xml:"summary,omitempty"`
+	Content   atomText    `xml:"content"`
+}
+
+// Atom returns the feed encoded as Atom 1.0.
+func Atom(f Feed) ([]byte, error) {
+	af := atomFeed{
+		Title:    f.Title,
+		Subtitle: f.Description,
+		ID:       f.Self,
+		Updated:  atomTime(f.updated()),
+		Links: []atomLink{
+			{Href: f.Link, Rel: "alternate", Type: "text/html"},
+			{Href: f.Self, Rel: "self", Type: "application/atom+xml"},
+		},
+	}
+	if f.Author != "" {
+		af.Author = &atomAuthor{Name: f.Author}
+	}
+
+	for _, it := range f.Items {
+		entry := atomEntry{
+			Title:   it.Title,
+			ID:      it.Link,
+			Link:    atomLink{Href: it.Link, Rel: "alternate", Type: "text/html"},
+			Updated: atomTime(it.updated()),
+			Content: atomText{Type: "html", Value: it.Content},
+		}
+		if !it.Published.IsZero() {
+			entry.Published = atomTime(it.Published)
+		}
+		if it.Author != "" {
+			entry.Author = &atomAuthor{Name: it.Author}
+		}
+		if it.Summary != "" {
+			entry.Summary = &atomText{Type: "text", Value: it.Summary}
+		}
+		af.Entries = append(af.Entries, entry)
+	}
+
+	return encode(af)
+}
+
+// updated returns the most recent update time of the feed items.
+func (f Feed) updated() time.Time {
+	var t time.Time
+	for _, it := range f.Items {
+		if u := it.updated(); u.After(t) {
+			t = u
+		}
+	}
+	return t
+}
+
+// updated returns the update time of the item, falling back to its
+// publish time.
+func (it Item) updated() time.Time {
+	if !it.Updated.IsZero() {
+		return it.Updated
+	}
+	return it.Published
+}
+
+// atomTime formats t as an RFC 3339 timestamp.
+func atomTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// encode returns v as an indented XML document.
+func encode(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/ericstrs/site/internal/config"
+	"github.com/ericstrs/site/internal/middleware"
+	"github.com/ericstrs/site/internal/render"
+)
+
+// errorMessages are shown to the user on the error page of a status.
+var errorMessages = map[int]string{
+	http.StatusNotFound:            "Sorry, but the page you were trying to view does not exist.",
+	http.StatusMethodNotAllowed:    "Sorry, but this page does not support that request method.",
+	http.StatusInternalServerError: "Sorry, something went wrong on our end.",
+	http.StatusServiceUnavailable:  "Sorry, the site is temporarily unavailable. Please try again later.",
+}
+
+// Error returns the error handler of the site. It writes the themed
+// error page of a status, or a JSON error when the client accepts
+// JSON.
+func Error(cfg *config.Config) middleware.ErrorHandler {
+	return func(w http.ResponseWriter, r *http.Request, status int) {
+		writeError(cfg, w, r, status)
+	}
+}
+
+// writeError writes the error page of the given status.
+func writeError(cfg *config.Config, w http.ResponseWriter, r *http.Request, status int) {
+	message, ok := errorMessages[status]
+	if !ok {
+		message = "Sorry, your request could not be completed."
+	}
+	requestID := middleware.GetRequestID(r)
+
+	w.Header().Del("Content-Length")
+
+	if strings.Contains(r.Header.Get("Accept"), "application/json") {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(struct {
+			Status    int    `json:"status"`
+			Error     string `json:"error"`
+			Message   string `json:"message"`
+			RequestID string `json:"request_id,omitempty"`
+		}{
+			Status:    status,
+			Error:     http.StatusText(status),
+			Message:   message,
+			RequestID: requestID,
+		})
+		return
+	}
+
+	// Error pages are served at any path, so they have no canonical
+	// URL.
+	l := newLayout(cfg, r, http.StatusText(status))
+	l.URL = ""
+
+	data := struct {
+		layout
+		Status     int
+		StatusText string
+		Message    string
+		RequestID  string
+	}{
+		layout:     l,
+		Status:     status,
+		StatusText: http.StatusText(status),
+		Message:    message,
+		RequestID:  requestID,
+	}
+
+	output, err := render.Template("error", data)
+	if err != nil {
+		slog.Error("failed to execute html template", "err", err,
+			"method", r.Method, "uri", r.URL.RequestURI(), "request_id", requestID,
+		)
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	w.Write(output)
+}
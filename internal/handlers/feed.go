@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/ericstrs/site/internal/config"
+	"github.com/ericstrs/site/internal/feed"
+	"github.com/ericstrs/site/internal/render"
+)
+
+// feedSize is the maximum number of items in a feed.
+const feedSize = 20
+
+// Feed handles the feed endpoints. It serves the most recent content
+// of the named sections as an RSS 2.0 feed, or as an Atom feed when
+// format is "atom".
+func Feed(cfg *config.Config, repo *render.Repository, format string, sections ...string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			siteURL = strings.TrimSuffix(cfg.URL, "/")
+
+			method = r.Method
+			uri    = r.URL.RequestURI()
+		)
+
+		// A section feed belongs to the section listing.
+		title, link := cfg.Title, siteURL+"/"
+		if len(sections) == 1 {
+			title += " - " + sections[0]
+			for _, sec := range cfg.Sections {
+				if sec.Name == sections[0] {
+					link = siteURL + sec.URL
+				}
+			}
+		}
+
+		f := feed.Feed{
+			Title:       title,
+			Description: cfg.Description,
+			Link:        link,
+			Self:        siteURL + r.URL.Path,
+			Author:      cfg.Title,
+		}
+
+		// Only the newest items are rendered.
+		var items []render.Content
+		for _, section := range sections {
+			items = append(items, repo.Recent(section, feedSize)...)
+		}
+		sort.SliceStable(items, func(i, j int) bool {
+			return items[i].PublishedAt.After(items[j].PublishedAt)
+		})
+		if len(items) > feedSize {
+			items = items[:feedSize]
+		}
+
+		for _, c := range items {
+			p, ok := repo.Document(c.Section, c.Id)
+			if !ok {
+				continue
+			}
+
+			f.Items = append(f.Items, feed.Item{
+				Title:     c.Title,
+				Link:      siteURL + c.URL,
+				Author:    c.Meta.Author,
+				Summary:   c.Meta.Summary,
+				Content:   render.FeedContent(p.Content, siteURL+c.URL),
+				Published: c.PublishedAt,
+				Updated:   c.UpdatedAt,
+			})
+		}
+
+		var (
+			output      []byte
+			err         error
+			contentType string
+		)
+		if format == "atom" {
+			output, err = feed.Atom(f)
+			contentType = "application/atom+xml; charset=utf-8"
+		} else {
+			output, err = feed.RSS(f)
+			contentType = "application/rss+xml; charset=utf-8"
+		}
+		if err != nil {
+			slog.Error("failed to encode feed", "err", err,
+				"method", method, "uri", uri,
+			)
+			writeError(cfg, w, r, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", contentType)
+		w.Write(output)
+	}
+}
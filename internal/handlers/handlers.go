@@ -2,67 +2,53 @@ package handlers
 
 import (
 	"html/template"
+	"io"
 	"log/slog"
 	"net/http"
-	"path/filepath"
+	"path"
+	"time"
 
 	"github.com/ericstrs/site/internal/config"
 	"github.com/ericstrs/site/internal/render"
 )
 
 // Home handles the home endpoint
-func Home(cfg *config.Config) http.HandlerFunc {
+func Home(cfg *config.Config, repo *render.Repository) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var (
-			title    = cfg.Title
-			filePath = filepath.Join(cfg.DocsPath, "README.md")
-
 			method = r.Method
 			uri    = r.URL.RequestURI()
 		)
 
-		p, err := render.LoadPage(title, filePath)
-		if err != nil {
-			slog.Error("failed to load home markdown file", "err", err,
+		p, ok := repo.Page("README.md")
+		if !ok {
+			slog.Error("home markdown file not found",
 				"method", method, "uri", uri,
 			)
-			http.Error(w, "error: something went wrong", http.StatusInternalServerError)
+			writeError(cfg, w, r, http.StatusInternalServerError)
 			return
 		}
 
-		recentBlogs, err := render.RecentContent("blogs", 5)
-		if err != nil {
-			slog.Error("failed to retrieve recent blogs", "err", err,
-				"method", method, "uri", uri,
-			)
-			http.Error(w, "error: something went wrong", http.StatusInternalServerError)
-			return
+		type recent struct {
+			Section config.Section
+			Items   []render.Content
 		}
-		recentNotes, err := render.RecentContent("notes", 5)
-		if err != nil {
-			slog.Error("failed to retrieve recent notes", "err", err,
-				"method", method, "uri", uri,
-			)
-			http.Error(w, "error: something went wrong", http.StatusInternalServerError)
-			return
+		var recents []recent
+		for _, sec := range cfg.Sections {
+			recents = append(recents, recent{
+				Section: sec,
+				Items:   repo.Recent(sec.Name, 5),
+			})
 		}
 
 		data := struct {
-			Nav         []config.NavItem
-			Social      []config.NavItem
-			Title       string
-			Description string
-			Content     template.HTML
-			RecentBlogs []render.Content
-			RecentNotes []render.Content
+			layout
+			Content template.HTML
+			Recent  []recent
 		}{
-			Nav:         cfg.Nav,
-			Social:      cfg.Social,
-			Title:       p.Title,
-			Description: cfg.Description,
-			Content:     template.HTML(string(p.Content)),
-			RecentBlogs: recentBlogs,
-			RecentNotes: recentNotes,
+			layout:  newLayout(cfg, r, ""),
+			Content: template.HTML(string(p.Content)),
+			Recent:  recents,
 		}
 
 		output, err := render.Template("home", data)
@@ -70,7 +56,7 @@ func Home(cfg *config.Config) http.HandlerFunc {
 			slog.Error("failed to execute html template", "err", err,
 				"method", method, "uri", uri,
 			)
-			http.Error(w, "error: something went wrong", http.StatusInternalServerError)
+			writeError(cfg, w, r, http.StatusInternalServerError)
 			return
 		}
 
@@ -79,37 +65,33 @@ func Home(cfg *config.Config) http.HandlerFunc {
 }
 
 // About handles the about endpoint
-func About(cfg *config.Config) http.HandlerFunc {
+func About(cfg *config.Config, repo *render.Repository) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var (
-			title    = cfg.Title
-			filePath = filepath.Join(cfg.DocsPath, "about.md")
-
 			method = r.Method
 			uri    = r.URL.RequestURI()
 		)
 
-		p, err := render.LoadPage(title, filePath)
-		if err != nil {
-			slog.Error("failed to load about markdown file", "err", err,
+		p, ok := repo.Page("about.md")
+		if !ok {
+			slog.Error("about markdown file not found",
 				"method", method, "uri", uri,
 			)
-			http.Error(w, "error: something went wrong", http.StatusInternalServerError)
+			writeError(cfg, w, r, http.StatusInternalServerError)
 			return
 		}
 
+		l := newLayout(cfg, r, p.Title)
+		if p.Description != "" {
+			l.Description = p.Description
+		}
+
 		data := struct {
-			Nav         []config.NavItem
-			Social      []config.NavItem
-			Title       string
-			Description string
-			Content     template.HTML
+			layout
+			Content template.HTML
 		}{
-			Nav:         cfg.Nav,
-			Social:      cfg.Social,
-			Title:       p.Title,
-			Description: cfg.Description,
-			Content:     template.HTML(string(p.Content)),
+			layout:  l,
+			Content: template.HTML(string(p.Content)),
 		}
 
 		output, err := render.Template("about", data)
@@ -117,7 +99,7 @@ func About(cfg *config.Config) http.HandlerFunc {
 			slog.Error("failed to execute html template", "err", err,
 				"method", method, "uri", uri,
 			)
-			http.Error(w, "error: something went wrong", http.StatusInternalServerError)
+			writeError(cfg, w, r, http.StatusInternalServerError)
 			return
 		}
 
@@ -125,57 +107,72 @@ func About(cfg *config.Config) http.HandlerFunc {
 	}
 }
 
-// Notes handles the notes endpoint
-func Notes(cfg *config.Config) http.HandlerFunc {
+// Section handles the listing endpoint of a section
+func Section(cfg *config.Config, repo *render.Repository, sec config.Section) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var (
-			title    = cfg.Title
-			filePath = filepath.Join(cfg.DocsPath, "notes", "README.md")
+			pageStr = r.PathValue("n")
 
 			method = r.Method
 			uri    = r.URL.RequestURI()
 		)
 
-		p, err := render.LoadPage(title, filePath)
-		if err != nil {
-			slog.Error("failed to load about markdown file", "err", err,
+		if pageStr == "" {
+			pageStr = r.URL.Query().Get("page")
+		}
+		page, ok := pageNumber(pageStr)
+		if !ok {
+			slog.Warn("invalid page number",
 				"method", method, "uri", uri,
 			)
-			http.Error(w, "error: something went wrong", http.StatusInternalServerError)
+			writeError(cfg, w, r, http.StatusNotFound)
 			return
 		}
 
-		notes, err := render.AllContent("notes")
-		if err != nil {
-			slog.Error("failed to retrieve notes", "err", err,
+		items, pagination, ok := paginate(sec, repo.All(sec.Name), page)
+		if !ok {
+			slog.Warn("page out of range",
 				"method", method, "uri", uri,
 			)
-			http.Error(w, "error: something went wrong", http.StatusInternalServerError)
+			writeError(cfg, w, r, http.StatusNotFound)
 			return
 		}
 
+		l := newLayout(cfg, r, sec.Name)
+		l.URL = absURL(cfg, PageURL(sec, page))
+
+		// The section README is an optional introduction.
+		var content template.HTML
+		if p, ok := repo.Page(path.Join(sec.Path, "README.md")); ok {
+			content = template.HTML(string(p.Content))
+			if p.Description != "" {
+				l.Description = p.Description
+			}
+		}
+
+		l.PrevURL = pagination.PrevURL
+		l.NextURL = pagination.NextURL
+
 		data := struct {
-			Nav         []config.NavItem
-			Social      []config.NavItem
-			Title       string
-			Description string
-			Content     template.HTML
-			Notes       []render.Content
+			layout
+			Content    template.HTML
+			Section    config.Section
+			Items      []render.Content
+			Pagination Pagination
 		}{
-			Nav:         cfg.Nav,
-			Social:      cfg.Social,
-			Title:       p.Title,
-			Description: cfg.Description,
-			Content:     template.HTML(string(p.Content)),
-			Notes:       notes,
+			layout:     l,
+			Content:    content,
+			Section:    sec,
+			Items:      items,
+			Pagination: pagination,
 		}
 
-		output, err := render.Template("notes", data)
+		output, err := render.Template(sec.ListTemplate, data)
 		if err != nil {
 			slog.Error("failed to execute html template", "err", err,
 				"method", method, "uri", uri,
 			)
-			http.Error(w, "error: something went wrong", http.StatusInternalServerError)
+			writeError(cfg, w, r, http.StatusInternalServerError)
 			return
 		}
 
@@ -183,47 +180,64 @@ func Notes(cfg *config.Config) http.HandlerFunc {
 	}
 }
 
-// Note handles the note endpoint
-func Note(cfg *config.Config) http.HandlerFunc {
+// Document handles the document endpoint of a section
+func Document(cfg *config.Config, repo *render.Repository, sec config.Section) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var (
-			title    = cfg.Title
-			idStr    = r.PathValue("id")
-			filePath = filepath.Join(cfg.DocsPath, "notes", idStr, "README.md")
+			idStr = r.PathValue("id")
 
 			method = r.Method
 			uri    = r.URL.RequestURI()
 		)
 
-		p, err := render.LoadPage(title, filePath)
-		if err != nil {
-			slog.Warn("markdown file not found", "err", err,
+		p, ok := repo.Document(sec.Name, idStr)
+		c, found := repo.Content(sec.Name, idStr)
+		if !ok || !found {
+			slog.Warn("markdown file not found",
 				"method", method, "uri", uri,
 			)
-			http.NotFound(w, r)
+			writeError(cfg, w, r, http.StatusNotFound)
 			return
 		}
 
+		tmpl := sec.Template
+		if p.Meta.Layout != "" {
+			tmpl = p.Meta.Layout
+		}
+		prev, next := repo.Adjacent(sec.Name, idStr)
+
 		data := struct {
-			Nav         []config.NavItem
-			Social      []config.NavItem
-			Title       string
-			Description string
+			layout
 			Content     template.HTML
+			Section     config.Section
+			Tags        []render.Tag
+			Status      string
+			TOC         []render.Heading
+			Date        time.Time
+			Updated     time.Time
+			ReadingTime int
+			Prev        *render.Content
+			Next        *render.Content
 		}{
-			Nav:         cfg.Nav,
-			Social:      cfg.Social,
-			Title:       p.Title,
-			Description: cfg.Description,
+			layout:      newDocumentLayout(cfg, r, tmpl, p, c),
 			Content:     template.HTML(string(p.Content)),
+			Section:     sec,
+			Tags:        render.NewTags(p.Meta.Tags),
+			Status:      p.Meta.Status(time.Now()),
+			TOC:         p.TOC,
+			Date:        c.PublishedAt,
+			Updated:     c.UpdatedAt,
+			ReadingTime: p.ReadingTime,
+			Prev:        prev,
+			Next:        next,
 		}
 
-		output, err := render.Template("note", data)
+		output, err := render.Template(tmpl, data)
 		if err != nil {
 			slog.Error("failed to execute html template", "err", err,
 				"method", method, "uri", uri,
 			)
-			http.Error(w, "error: something went wrong", http.StatusInternalServerError)
+			writeError(cfg, w, r, http.StatusInternalServerError)
 			return
 		}
 
@@ -231,57 +245,79 @@ func Note(cfg *config.Config) http.HandlerFunc {
 	}
 }
 
-// Blogs handles the blogs endpoint
-func Blogs(cfg *config.Config) http.HandlerFunc {
+// Asset handles the endpoint of a file beside a document of a section
+func Asset(cfg *config.Config, repo *render.Repository, sec config.Section) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var (
-			title    = cfg.Title
-			filePath = filepath.Join(cfg.DocsPath, "blogs", "README.md")
+			idStr = r.PathValue("id")
+			file  = r.PathValue("file")
 
 			method = r.Method
 			uri    = r.URL.RequestURI()
 		)
 
-		p, err := render.LoadPage(title, filePath)
+		f, err := repo.OpenAsset(sec.Name, idStr, file)
 		if err != nil {
-			slog.Error("failed to load about markdown file", "err", err,
+			slog.Warn("asset not found", "err", err,
 				"method", method, "uri", uri,
 			)
-			http.Error(w, "error: something went wrong", http.StatusInternalServerError)
+			writeError(cfg, w, r, http.StatusNotFound)
 			return
 		}
+		defer f.Close()
 
-		blogs, err := render.AllContent("blogs")
+		info, err := f.Stat()
 		if err != nil {
-			slog.Error("failed to retrieve blogs", "err", err,
+			slog.Error("failed to stat asset", "err", err,
 				"method", method, "uri", uri,
 			)
-			http.Error(w, "error: something went wrong", http.StatusInternalServerError)
+			writeError(cfg, w, r, http.StatusInternalServerError)
 			return
 		}
 
+		content, ok := f.(io.ReadSeeker)
+		if !ok {
+			slog.Error("asset does not support seeking",
+				"method", method, "uri", uri,
+			)
+			writeError(cfg, w, r, http.StatusInternalServerError)
+			return
+		}
+
+		http.ServeContent(w, r, info.Name(), info.ModTime(), content)
+	}
+}
+
+// Stylesheet handles a generated stylesheet endpoint
+func Stylesheet(css []byte) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/css; charset=utf-8")
+		w.Write(css)
+	}
+}
+
+// Tags handles the tags endpoint
+func Tags(cfg *config.Config, repo *render.Repository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			method = r.Method
+			uri    = r.URL.RequestURI()
+		)
+
 		data := struct {
-			Nav         []config.NavItem
-			Social      []config.NavItem
-			Title       string
-			Description string
-			Content     template.HTML
-			Blogs       []render.Content
+			layout
+			Tags []render.Tag
 		}{
-			Nav:         cfg.Nav,
-			Social:      cfg.Social,
-			Title:       p.Title,
-			Description: cfg.Description,
-			Content:     template.HTML(string(p.Content)),
-			Blogs:       blogs,
+			layout: newLayout(cfg, r, "Tags"),
+			Tags:   repo.Tags(),
 		}
 
-		output, err := render.Template("blogs", data)
+		output, err := render.Template("tags", data)
 		if err != nil {
 			slog.Error("failed to execute html template", "err", err,
 				"method", method, "uri", uri,
 			)
-			http.Error(w, "error: something went wrong", http.StatusInternalServerError)
+			writeError(cfg, w, r, http.StatusInternalServerError)
 			return
 		}
 
@@ -289,47 +325,41 @@ func Blogs(cfg *config.Config) http.HandlerFunc {
 	}
 }
 
-// Blog handles the blog endpoint
-func Blog(cfg *config.Config) http.HandlerFunc {
+// Tag handles the tag endpoint
+func Tag(cfg *config.Config, repo *render.Repository) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var (
-			title    = cfg.Title
-			idStr    = r.PathValue("id")
-			filePath = filepath.Join(cfg.DocsPath, "blogs", idStr, "README.md")
+			slug = r.PathValue("tag")
 
 			method = r.Method
 			uri    = r.URL.RequestURI()
 		)
 
-		p, err := render.LoadPage(title, filePath)
-		if err != nil {
-			slog.Warn("markdown file not found", "err", err,
+		tag, items, ok := repo.Tagged(slug)
+		if !ok {
+			slog.Warn("tag not found",
 				"method", method, "uri", uri,
 			)
-			http.NotFound(w, r)
+			writeError(cfg, w, r, http.StatusNotFound)
 			return
 		}
 
 		data := struct {
-			Nav         []config.NavItem
-			Social      []config.NavItem
-			Title       string
-			Description string
-			Content     template.HTML
+			layout
+			Tag   render.Tag
+			Items []render.Content
 		}{
-			Nav:         cfg.Nav,
-			Social:      cfg.Social,
-			Title:       p.Title,
-			Description: cfg.Description,
-			Content:     template.HTML(string(p.Content)),
+			layout: newLayout(cfg, r, tag.Name),
+			Tag:    tag,
+			Items:  items,
 		}
 
-		output, err := render.Template("note", data)
+		output, err := render.Template("tag", data)
 		if err != nil {
 			slog.Error("failed to execute html template", "err", err,
 				"method", method, "uri", uri,
 			)
-			http.Error(w, "error: something went wrong", http.StatusInternalServerError)
+			writeError(cfg, w, r, http.StatusInternalServerError)
 			return
 		}
 
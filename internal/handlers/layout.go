@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ericstrs/site/internal/config"
+	"github.com/ericstrs/site/internal/render"
+)
+
+// layout holds the data shared by every page through the head, header
+// and footer templates.
+type layout struct {
+	Nav         []config.NavItem
+	Social      []config.NavItem
+	Title       string
+	Description string
+
+	// PageTitle is the title of the page, shown before the site title.
+	// It is empty on the home page.
+	PageTitle string
+
+	// URL is the canonical URL of the page.
+	URL string
+
+	// Image is the absolute URL of the cover image of the page.
+	Image string
+
+	// Article describes the document shown by the page, if any.
+	Article *article
+
+	// PrevURL and NextURL link to the neighbouring pages of a
+	// paginated listing.
+	PrevURL string
+	NextURL string
+}
+
+// article is the metadata of a document page used by Open Graph tags
+// and structured data.
+type article struct {
+	// Type is the schema.org type of the document.
+	Type      string
+	Published time.Time
+	Modified  time.Time
+	Author    string
+	Tags      []string
+}
+
+// newLayout returns the layout data for the page of the site with the
+// given title served at r.
+func newLayout(cfg *config.Config, r *http.Request, title string) layout {
+	return layout{
+		Nav:         cfg.Nav,
+		Social:      cfg.Social,
+		Title:       cfg.Title,
+		Description: cfg.Description,
+		PageTitle:   title,
+		URL:         absURL(cfg, r.URL.Path),
+	}
+}
+
+// newDocumentLayout returns the layout data for the page of a document
+// rendered with the template tmpl.
+func newDocumentLayout(cfg *config.Config, r *http.Request, tmpl string, p *render.Page, c render.Content) layout {
+	l := newLayout(cfg, r, p.Title)
+	if p.Description != "" {
+		l.Description = p.Description
+	}
+	if p.Meta.Image != "" {
+		l.Image = l.resolve(p.Meta.Image)
+	}
+
+	typ := "Article"
+	if tmpl == "blog" {
+		typ = "BlogPosting"
+	}
+	l.Article = &article{
+		Type:      typ,
+		Published: c.PublishedAt,
+		Modified:  c.UpdatedAt,
+		Author:    p.Meta.Author,
+		Tags:      p.Meta.Tags,
+	}
+	return l
+}
+
+// resolve returns the absolute URL of ref relative to the page.
+func (l layout) resolve(ref string) string {
+	base, err := url.Parse(l.URL + "/")
+	if err != nil {
+		return ref
+	}
+	u, err := url.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return base.ResolveReference(u).String()
+}
+
+// StructuredData returns the JSON-LD description of the document shown
+// by the page.
+func (l layout) StructuredData() map[string]any {
+	if l.Article == nil {
+		return nil
+	}
+
+	data := map[string]any{
+		"@context":         "https://schema.org",
+		"@type":            l.Article.Type,
+		"headline":         l.PageTitle,
+		"description":      l.Description,
+		"url":              l.URL,
+		"mainEntityOfPage": l.URL,
+		"publisher": map[string]any{
+			"@type": "Organization",
+			"name":  l.Title,
+		},
+	}
+	if !l.Article.Published.IsZero() {
+		data["datePublished"] = l.Article.Published.Format(time.RFC3339)
+	}
+	if !l.Article.Modified.IsZero() {
+		data["dateModified"] = l.Article.Modified.Format(time.RFC3339)
+	}
+	if l.Article.Author != "" {
+		data["author"] = map[string]any{"@type": "Person", "name": l.Article.Author}
+	}
+	if l.Image != "" {
+		data["image"] = l.Image
+	}
+	if len(l.Article.Tags) > 0 {
+		data["keywords"] = strings.Join(l.Article.Tags, ", ")
+	}
+	return data
+}
+
+// absURL returns the absolute URL of the site path p.
+func absURL(cfg *config.Config, p string) string {
+	return strings.TrimSuffix(cfg.URL, "/") + p
+}
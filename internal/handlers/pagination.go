@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/ericstrs/site/internal/config"
+	"github.com/ericstrs/site/internal/render"
+)
+
+// Pagination describes one page of a paginated listing.
+type Pagination struct {
+	Page    int
+	Pages   int
+	PrevURL string
+	NextURL string
+}
+
+// PageURL returns the URL of page n of the section listing. The first
+// page is the listing itself.
+func PageURL(sec config.Section, n int) string {
+	if n <= 1 {
+		return sec.URL
+	}
+	return sec.URL + "/page/" + strconv.Itoa(n)
+}
+
+// PageCount returns the number of listing pages for n items in the
+// section. There is always at least one page.
+func PageCount(sec config.Section, n int) int {
+	if sec.PageSize <= 0 || n == 0 {
+		return 1
+	}
+	return (n + sec.PageSize - 1) / sec.PageSize
+}
+
+// paginate returns the items on the given page of the section listing
+// and its pagination. It reports false if the page is out of range.
+func paginate(sec config.Section, items []render.Content, page int) ([]render.Content, Pagination, bool) {
+	pages := PageCount(sec, len(items))
+	if page < 1 || page > pages {
+		return nil, Pagination{}, false
+	}
+
+	p := Pagination{Page: page, Pages: pages}
+	if page > 1 {
+		p.PrevURL = PageURL(sec, page-1)
+	}
+	if page < pages {
+		p.NextURL = PageURL(sec, page+1)
+	}
+
+	if sec.PageSize > 0 {
+		start := (page - 1) * sec.PageSize
+		end := min(start+sec.PageSize, len(items))
+		items = items[start:end]
+	}
+
+	return items, p, true
+}
+
+// pageNumber returns the requested page number from the "n" path value
+// or the "page" query parameter, defaulting to the first page. It
+// reports false if the number is malformed.
+func pageNumber(s string) (int, bool) {
+	if s == "" {
+		return 1, true
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/ericstrs/site/internal/config"
+	"github.com/ericstrs/site/internal/render"
+)
+
+// Search handles the search endpoint
+func Search(cfg *config.Config, repo *render.Repository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			query = strings.TrimSpace(r.URL.Query().Get("q"))
+
+			method = r.Method
+			uri    = r.URL.RequestURI()
+		)
+
+		var results []render.SearchResult
+		if query != "" {
+			results = repo.Search(query)
+		}
+
+		data := struct {
+			layout
+			Query       string
+			Placeholder string
+			Results     []render.SearchResult
+		}{
+			layout:      newLayout(cfg, r, "Search"),
+			Query:       query,
+			Placeholder: searchPlaceholder(cfg.Sections),
+			Results:     results,
+		}
+
+		output, err := render.Template("search", data)
+		if err != nil {
+			slog.Error("failed to execute html template", "err", err,
+				"method", method, "uri", uri,
+			)
+			writeError(cfg, w, r, http.StatusInternalServerError)
+			return
+		}
+
+		w.Write(output)
+	}
+}
+
+// searchPlaceholder returns the hint of the search box, naming the
+// sections searched, such as "search blogs and notes".
+func searchPlaceholder(sections []config.Section) string {
+	names := make([]string, len(sections))
+	for i, sec := range sections {
+		names[i] = strings.ToLower(sec.Name)
+	}
+	if len(names) < 2 {
+		return "search " + strings.Join(names, "")
+	}
+	return "search " + strings.Join(names[:len(names)-1], ", ") + " and " + names[len(names)-1]
+}
+
+// SearchJSON handles the JSON search endpoint
+func SearchJSON(cfg *config.Config, repo *render.Repository) http.HandlerFunc {
+	type result struct {
+		Title   string `json:"title"`
+		URL     string `json:"url"`
+		Section string `json:"section"`
+		Snippet string `json:"snippet"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			query = strings.TrimSpace(r.URL.Query().Get("q"))
+
+			method = r.Method
+			uri    = r.URL.RequestURI()
+		)
+
+		results := []result{}
+		if query != "" {
+			for _, res := range repo.Search(query) {
+				results = append(results, result{
+					Title:   res.Title,
+					URL:     res.URL,
+					Section: res.Section,
+					Snippet: string(res.Snippet),
+				})
+			}
+		}
+
+		output, err := json.Marshal(struct {
+			Query   string   `json:"query"`
+			Results []result `json:"results"`
+		}{query, results})
+		if err != nil {
+			slog.Error("failed to encode search results", "err", err,
+				"method", method, "uri", uri,
+			)
+			writeError(cfg, w, r, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(output)
+	}
+}
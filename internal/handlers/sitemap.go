@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ericstrs/site/internal/config"
+	"github.com/ericstrs/site/internal/render"
+	"github.com/ericstrs/site/internal/sitemap"
+)
+
+// Sitemap handles the sitemap endpoint. It lists every page of the
+// site, dated by the most recent update of the content it shows.
+func Sitemap(cfg *config.Config, repo *render.Repository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			method = r.Method
+			uri    = r.URL.RequestURI()
+		)
+
+		var (
+			urls   []sitemap.URL
+			newest time.Time
+		)
+		for _, sec := range cfg.Sections {
+			items := repo.All(sec.Name)
+			lastMod := lastUpdated(items)
+			if lastMod.After(newest) {
+				newest = lastMod
+			}
+
+			for n := 1; n <= PageCount(sec, len(items)); n++ {
+				urls = append(urls, sitemap.URL{
+					Loc:     absURL(cfg, PageURL(sec, n)),
+					LastMod: lastMod,
+				})
+			}
+			for _, c := range items {
+				urls = append(urls, sitemap.URL{
+					Loc:        absURL(cfg, c.URL),
+					LastMod:    c.UpdatedAt,
+					ChangeFreq: c.Meta.ChangeFreq,
+					Priority:   c.Meta.Priority,
+				})
+			}
+		}
+
+		fixed := []sitemap.URL{
+			{Loc: absURL(cfg, "/"), LastMod: newest},
+			{Loc: absURL(cfg, "/about")},
+			{Loc: absURL(cfg, "/tags"), LastMod: newest},
+		}
+		for _, tag := range repo.Tags() {
+			_, items, _ := repo.Tagged(tag.Slug)
+			fixed = append(fixed, sitemap.URL{
+				Loc:     absURL(cfg, "/tags/"+tag.Slug),
+				LastMod: lastUpdated(items),
+			})
+		}
+		urls = append(fixed, urls...)
+
+		output, err := sitemap.Sitemap(urls)
+		if err != nil {
+			slog.Error("failed to encode sitemap", "err", err,
+				"method", method, "uri", uri,
+			)
+			writeError(cfg, w, r, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+		w.Write(output)
+	}
+}
+
+// Robots handles the robots.txt endpoint. It writes the configured
+// rules, allowing every crawler when there are none, and references
+// the sitemap.
+func Robots(cfg *config.Config) http.HandlerFunc {
+	rules := cfg.Robots
+	if len(rules) == 0 {
+		rules = []config.RobotsRule{{UserAgent: "*"}}
+	}
+
+	var b strings.Builder
+	for _, rule := range rules {
+		userAgent := rule.UserAgent
+		if userAgent == "" {
+			userAgent = "*"
+		}
+		fmt.Fprintf(&b, "User-agent: %s\n", userAgent)
+		for _, p := range rule.Allow {
+			fmt.Fprintf(&b, "Allow: %s\n", p)
+		}
+		for _, p := range rule.Disallow {
+			fmt.Fprintf(&b, "Disallow: %s\n", p)
+		}
+		if len(rule.Allow) == 0 && len(rule.Disallow) == 0 {
+			b.WriteString("Disallow:\n")
+		}
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "Sitemap: %s\n", absURL(cfg, "/sitemap.xml"))
+	robots := []byte(b.String())
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Write(robots)
+	}
+}
+
+// lastUpdated returns the most recent update time of items.
+func lastUpdated(items []render.Content) time.Time {
+	var t time.Time
+	for _, c := range items {
+		if c.UpdatedAt.After(t) {
+			t = c.UpdatedAt
+		}
+	}
+	return t
+}
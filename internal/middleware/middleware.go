@@ -1,6 +1,9 @@
 package middleware
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -26,7 +29,7 @@ func LogRequest(next http.Handler) http.Handler {
 			uri     = r.RequestURI
 		)
 
-		slog.Info("Request", "method", method, "took", took, "referer", referer, "remote_addr", addr, "uri", uri)
+		slog.Info("Request", "method", method, "took", took, "referer", referer, "remote_addr", addr, "uri", uri, "request_id", GetRequestID(r))
 	})
 }
 
@@ -47,20 +50,93 @@ func formatDuration(d time.Duration) string {
 	return fmt.Sprintf("%.2fm", m)
 }
 
+// ErrorHandler writes the error response of the given status.
+type ErrorHandler func(w http.ResponseWriter, r *http.Request, status int)
+
 // PanicRecovery is middleware for recovering from panics in `next` and
-// returning a StatusInternalServerError to the client.
-func PanicRecovery(next http.Handler) http.Handler {
+// returning a StatusInternalServerError to the client through
+// onError.
+func PanicRecovery(onError ErrorHandler, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		defer func() {
 			if err := recover(); err != nil {
-				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
-				slog.Error("Server failed", "err", err, "trace", string(debug.Stack()))
+				onError(w, r, http.StatusInternalServerError)
+				slog.Error("Server failed", "err", err, "request_id", GetRequestID(r), "trace", string(debug.Stack()))
 			}
 		}()
 		next.ServeHTTP(w, r)
 	})
 }
 
+// Errors is middleware replacing the plain text error responses of
+// `next`, such as the not found and method not allowed responses of
+// http.ServeMux and http.FileServer, with the response of onError.
+func Errors(onError ErrorHandler, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(&errorWriter{ResponseWriter: w, r: r, onError: onError}, r)
+	})
+}
+
+// errorWriter intercepts plain text error responses, as written by
+// http.Error, and writes the response of onError instead.
+type errorWriter struct {
+	http.ResponseWriter
+	r       *http.Request
+	onError ErrorHandler
+
+	// intercepted is set once the error response has been written and
+	// the body of the original response must be discarded.
+	intercepted bool
+}
+
+func (ew *errorWriter) WriteHeader(status int) {
+	if status >= 400 && strings.HasPrefix(ew.Header().Get("Content-Type"), "text/plain") {
+		ew.intercepted = true
+		ew.onError(ew.ResponseWriter, ew.r, status)
+		return
+	}
+	ew.ResponseWriter.WriteHeader(status)
+}
+
+func (ew *errorWriter) Write(b []byte) (int, error) {
+	if ew.intercepted {
+		return len(b), nil
+	}
+	return ew.ResponseWriter.Write(b)
+}
+
+// Unwrap returns the underlying ResponseWriter for
+// http.ResponseController.
+func (ew *errorWriter) Unwrap() http.ResponseWriter {
+	return ew.ResponseWriter
+}
+
+type contextKey int
+
+const requestIDKey contextKey = iota
+
+// RequestID is middleware assigning every request a random ID. The ID
+// is returned in the X-Request-Id header so users can quote it, and is
+// available to handlers through GetRequestID.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b := make([]byte, 8)
+		rand.Read(b)
+		id := hex.EncodeToString(b)
+
+		w.Header().Set("X-Request-Id", id)
+		ctx := context.WithValue(r.Context(), requestIDKey, id)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// GetRequestID returns the ID assigned to r by RequestID, or an empty
+// string.
+func GetRequestID(r *http.Request) string {
+	id, _ := r.Context().Value(requestIDKey).(string)
+	return id
+}
+
 // SecurityHeaders middleware function for logging requests
 func SecurityHeaders(cfg *config.Config, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
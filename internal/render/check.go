@@ -0,0 +1,90 @@
+package render
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/ericstrs/site/internal/config"
+	"github.com/ericstrs/site/internal/sitemap"
+)
+
+// Check parses and renders every markdown document of the docs
+// directory fsys and returns the problems found, one error per
+// document.
+func Check(fsys fs.FS) []error {
+	var errs []error
+
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || path.Ext(name) != ".md" {
+			return nil
+		}
+
+		// Documents inside a content directory are listed by title.
+		listed := path.Dir(path.Dir(name)) != "."
+
+		if err := checkDocument(fsys, name, listed); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+		return nil
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	return errs
+}
+
+// checkDocument reports whether the named document of fsys has valid
+// front matter, a loaded layout template and renders to HTML. Listed
+// documents must also have a title.
+func checkDocument(fsys fs.FS, name string, listed bool) error {
+	content, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return err
+	}
+
+	meta, body, err := parseDocument(content)
+	if err != nil {
+		return err
+	}
+
+	if listed && meta.Title == "" && !hasHeading(body) {
+		return fmt.Errorf("missing title")
+	}
+	if meta.Layout != "" && !HasTemplate(meta.Layout) {
+		return fmt.Errorf("layout %q not found", meta.Layout)
+	}
+
+	if meta.ChangeFreq != "" && !slices.Contains(sitemap.ChangeFreqs, meta.ChangeFreq) {
+		return fmt.Errorf("invalid changefreq %q", meta.ChangeFreq)
+	}
+	if p := meta.Priority; p != nil && (*p < 0 || *p > 1) {
+		return fmt.Errorf("priority %v is not between 0 and 1", *p)
+	}
+
+	if _, _, err := markdownToHTML(body, "", config.TOC{}); err != nil {
+		return fmt.Errorf("failed to render markdown: %w", err)
+	}
+
+	return nil
+}
+
+// hasHeading reports whether the first line of body that is not blank
+// is a markdown heading with text, the title used without one in the
+// front matter.
+func hasHeading(body []byte) bool {
+	for _, line := range strings.Split(string(body), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		return strings.HasPrefix(line, "#") && strings.TrimLeft(line, "# ") != ""
+	}
+	return false
+}
@@ -0,0 +1,35 @@
+package render
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		ok   bool
+	}{
+		{"front matter title", "---\ntitle: Hello\n---\nBody\n", true},
+		{"heading title", "\n# Hello\n\nBody\n", true},
+		{"missing title", "Body\n", false},
+		{"empty heading", "#\n\nBody\n", false},
+		{"known layout", "---\nlayout: blog\n---\n# Hello\n", true},
+		{"unknown layout", "---\nlayout: missing\n---\n# Hello\n", false},
+		{"invalid changefreq", "---\nchangefreq: often\n---\n# Hello\n", false},
+		{"invalid priority", "---\npriority: 2\n---\n# Hello\n", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := fstest.MapFS{"notes/doc/README.md": {Data: []byte(tt.doc)}}
+			errs := Check(fsys)
+			if tt.ok && len(errs) > 0 {
+				t.Errorf("Check() = %v, want no errors", errs)
+			}
+			if !tt.ok && len(errs) != 1 {
+				t.Errorf("Check() = %v, want one error", errs)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package render
+
+import (
+	"bufio"
+	"bytes"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// fileDates holds the times of the first and the last commit that
+// touched a file.
+type fileDates struct {
+	created  time.Time
+	modified time.Time
+}
+
+// gitDates returns the commit dates of every file tracked by git
+// beneath root, keyed by slash separated path relative to root. It
+// returns an empty map when git is unavailable or root is not inside
+// a git repository.
+func gitDates(root string) map[string]fileDates {
+	dates := make(map[string]fileDates)
+
+	// core.quotePath=false lists non-ASCII paths as is rather than
+	// quoted and escaped.
+	cmd := exec.Command("git", "-C", root, "-c", "core.quotePath=false",
+		"log", "--format=%x00%aI", "--name-only", "--relative", "--", ".")
+	out, err := cmd.Output()
+	if err != nil {
+		return dates
+	}
+
+	// Commits are listed newest first, so the first date seen for a
+	// file is its last modification and the last one is its creation.
+	var date time.Time
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "\x00") {
+			date, _ = time.Parse(time.RFC3339, line[1:])
+			continue
+		}
+		if line == "" || date.IsZero() {
+			continue
+		}
+
+		d, ok := dates[line]
+		if !ok {
+			d.modified = date
+		}
+		d.created = date
+		dates[line] = d
+	}
+
+	return dates
+}
+
+// contentDates returns the publish and update times of a document.
+// Each comes from the front matter ("date" and "updated"), falling
+// back to the git history of the file and finally to its modification
+// time.
+func contentDates(meta FrontMatter, git fileDates, modTime time.Time) (published, updated time.Time) {
+	published = firstTime(meta.Date, git.created, modTime)
+	updated = firstTime(meta.Updated, git.modified, modTime)
+	if updated.Before(published) {
+		updated = published
+	}
+	return published, updated
+}
+
+// firstTime returns the first non-zero time.
+func firstTime(times ...time.Time) time.Time {
+	for _, t := range times {
+		if !t.IsZero() {
+			return t
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,68 @@
+package render
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestGitDates(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+
+	root := t.TempDir()
+	git := func(date string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", root}, args...)...)
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=a", "GIT_AUTHOR_EMAIL=a@example.com", "GIT_AUTHOR_DATE="+date,
+			"GIT_COMMITTER_NAME=a", "GIT_COMMITTER_EMAIL=a@example.com", "GIT_COMMITTER_DATE="+date,
+		)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	git("", "init", "-q")
+	write("blogs/café/README.md", "# Café\n")
+	write("notes/n1/README.md", "# N1\n")
+	git("2024-01-01T00:00:00Z", "add", ".")
+	git("2024-01-01T00:00:00Z", "commit", "-q", "-m", "first")
+	write("blogs/café/README.md", "# Café au lait\n")
+	git("2024-02-01T00:00:00Z", "commit", "-q", "-am", "second")
+
+	dates := gitDates(root)
+	tests := []struct {
+		name              string
+		created, modified string
+	}{
+		{"blogs/café/README.md", "2024-01-01", "2024-02-01"},
+		{"notes/n1/README.md", "2024-01-01", "2024-01-01"},
+	}
+	for _, tt := range tests {
+		d, ok := dates[tt.name]
+		if !ok {
+			t.Errorf("no dates for %s in %v", tt.name, dates)
+			continue
+		}
+		if got := d.created.UTC().Format(time.DateOnly); got != tt.created {
+			t.Errorf("%s created %s, want %s", tt.name, got, tt.created)
+		}
+		if got := d.modified.UTC().Format(time.DateOnly); got != tt.modified {
+			t.Errorf("%s modified %s, want %s", tt.name, got, tt.modified)
+		}
+	}
+}
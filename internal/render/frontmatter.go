@@ -0,0 +1,129 @@
+package render
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// FrontMatter represents the metadata declared at the top of a
+// markdown document, either as YAML between "---" lines or as TOML
+// between "+++" lines.
+type FrontMatter struct {
+	Title   string    `yaml:"title" toml:"title"`
+	Date    time.Time `yaml:"date" toml:"date"`
+	Updated time.Time `yaml:"updated" toml:"updated"`
+	Summary string    `yaml:"summary" toml:"summary"`
+	Tags    []string  `yaml:"tags" toml:"tags"`
+	Draft   bool      `yaml:"draft" toml:"draft"`
+	Slug    string    `yaml:"slug" toml:"slug"`
+	Author  string    `yaml:"author" toml:"author"`
+
+	// Image is the cover image shown when the page is shared: an
+	// absolute URL, a path on the site or a path relative to the page.
+	Image string `yaml:"image" toml:"image"`
+
+	// ChangeFreq and Priority are the sitemap hints of the page.
+	// Priority is nil when unset.
+	ChangeFreq string   `yaml:"changefreq" toml:"changefreq"`
+	Priority   *float64 `yaml:"priority" toml:"priority"`
+
+	// TOC set to false hides the table of contents of the page.
+	TOC *bool `yaml:"toc" toml:"toc"`
+
+	// Layout names the template rendering the document in place of
+	// the template of its section.
+	Layout string `yaml:"layout" toml:"layout"`
+}
+
+// Publishing status of a document.
+const (
+	StatusPublished = ""
+	StatusDraft     = "draft"
+	StatusScheduled = "scheduled"
+)
+
+// Status returns the publishing status at time now: StatusDraft for
+// drafts, StatusScheduled for documents dated after now and
+// StatusPublished otherwise.
+func (fm FrontMatter) Status(now time.Time) string {
+	switch {
+	case fm.Draft:
+		return StatusDraft
+	case fm.Date.After(now):
+		return StatusScheduled
+	default:
+		return StatusPublished
+	}
+}
+
+// parseDocument splits the given markdown into its front matter and
+// body. Documents without front matter return a zero FrontMatter and
+// the content unchanged.
+func parseDocument(content []byte) (FrontMatter, []byte, error) {
+	var fm FrontMatter
+
+	raw, body, delim := splitFrontMatter(content)
+	if delim == "" {
+		return fm, content, nil
+	}
+
+	switch delim {
+	case "---":
+		dec := yaml.NewDecoder(bytes.NewReader(raw))
+		dec.KnownFields(true)
+		if err := dec.Decode(&fm); err != nil && !errors.Is(err, io.EOF) {
+			return fm, nil, fmt.Errorf("failed to unmarshal yaml front matter: %w", err)
+		}
+	case "+++":
+		md, err := toml.Decode(string(raw), &fm)
+		if err != nil {
+			return fm, nil, fmt.Errorf("failed to unmarshal toml front matter: %w", err)
+		}
+		if keys := md.Undecoded(); len(keys) > 0 {
+			return fm, nil, fmt.Errorf("failed to unmarshal toml front matter: unknown key %q", keys[0].String())
+		}
+	}
+
+	return fm, body, nil
+}
+
+// splitFrontMatter returns the raw front matter, the remaining body
+// and the delimiter used. The delimiter is empty when the content
+// does not start with front matter.
+func splitFrontMatter(content []byte) ([]byte, []byte, string) {
+	content = bytes.TrimPrefix(content, []byte("\ufeff"))
+
+	for _, delim := range []string{"---", "+++"} {
+		first, rest, ok := cutLine(content)
+		if !ok || strings.TrimSpace(string(first)) != delim {
+			continue
+		}
+
+		var raw []byte
+		for len(rest) > 0 {
+			line, next, _ := cutLine(rest)
+			if strings.TrimSpace(string(line)) == delim {
+				return raw, next, delim
+			}
+			raw = append(raw, line...)
+			raw = append(raw, '\n')
+			rest = next
+		}
+	}
+
+	return nil, content, ""
+}
+
+// cutLine slices b around the first newline, dropping any trailing
+// carriage return from the line.
+func cutLine(b []byte) (line, rest []byte, found bool) {
+	line, rest, found = bytes.Cut(b, []byte("\n"))
+	return bytes.TrimSuffix(line, []byte("\r")), rest, found
+}
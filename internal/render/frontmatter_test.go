@@ -0,0 +1,99 @@
+package render
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseDocument(t *testing.T) {
+	date := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	toc := false
+	priority := 1.0
+	tests := []struct {
+		name    string
+		doc     string
+		want    FrontMatter
+		body    string
+		wantErr bool
+	}{
+		{
+			name: "none",
+			doc:  "# Title\n",
+			body: "# Title\n",
+		},
+		{
+			name: "yaml",
+			doc:  "---\ntitle: Hello\ndate: 2024-03-01\ntags: [go, \"a, b\"]\ndraft: true # wip\npriority: 1\ntoc: false\n---\nBody\n",
+			want: FrontMatter{Title: "Hello", Date: date, Tags: []string{"go", "a, b"}, Draft: true, Priority: &priority, TOC: &toc},
+			body: "Body\n",
+		},
+		{
+			name: "yaml empty",
+			doc:  "---\n---\nBody\n",
+			body: "Body\n",
+		},
+		{
+			name:    "yaml unknown key",
+			doc:     "---\ntitel: Hello\n---\n",
+			wantErr: true,
+		},
+		{
+			name:    "yaml wrong type",
+			doc:     "---\ndraft: maybe\n---\n",
+			wantErr: true,
+		},
+		{
+			name: "toml",
+			doc:  "+++\ntitle = \"Hello\"\ndate = 2024-03-01\ntags = [\"go\", \"a, b\"]\ndraft = true # wip\npriority = 1\ntoc = false\n+++\nBody\n",
+			want: FrontMatter{Title: "Hello", Date: date, Tags: []string{"go", "a, b"}, Draft: true, Priority: &priority, TOC: &toc},
+			body: "Body\n",
+		},
+		{
+			name: "toml multi-line array",
+			doc:  "+++\ntags = [\n  \"go\",\n  'web', # trailing comma\n]\n+++\nBody\n",
+			want: FrontMatter{Tags: []string{"go", "web"}},
+			body: "Body\n",
+		},
+		{
+			name:    "toml unknown key",
+			doc:     "+++\ntitel = \"Hello\"\n+++\n",
+			wantErr: true,
+		},
+		{
+			name:    "toml wrong type",
+			doc:     "+++\ndraft = \"yes\"\n+++\n",
+			wantErr: true,
+		},
+		{
+			name:    "toml syntax",
+			doc:     "+++\ntitle = Hello\n+++\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fm, body, err := parseDocument([]byte(tt.doc))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseDocument() = %+v, want error", fm)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			// TOML local dates are in the local time zone.
+			if fm.Date.Format(time.DateOnly) != tt.want.Date.Format(time.DateOnly) {
+				t.Errorf("date = %v, want %v", fm.Date, tt.want.Date)
+			}
+			fm.Date = tt.want.Date
+			if !reflect.DeepEqual(fm, tt.want) {
+				t.Errorf("front matter = %+v, want %+v", fm, tt.want)
+			}
+			if string(body) != tt.body {
+				t.Errorf("body = %q, want %q", body, tt.body)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package render
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/alecthomas/chroma/v2/styles"
+)
+
+// highlightOptions are the chroma formatting options shared by the
+// markdown renderer and the generated stylesheet. Code is emitted with
+// CSS classes so the theme can follow the reader's color scheme.
+var highlightOptions = []chromahtml.Option{
+	chromahtml.WithClasses(true),
+	chromahtml.WithLineNumbers(true),
+	chromahtml.WrapLongLines(true),
+}
+
+// CheckStyle returns an error if chroma has no style with the given
+// name.
+func CheckStyle(name string) error {
+	if _, ok := styles.Registry[name]; ok {
+		return nil
+	}
+
+	names := make([]string, 0, len(styles.Registry))
+	for n := range styles.Registry {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return fmt.Errorf("unknown syntax highlighting theme %q, available themes: %s",
+		name, strings.Join(names, ", "))
+}
+
+// HighlightCSS returns the stylesheet for highlighted code. The light
+// and dark themes apply according to the reader's prefers-color-scheme
+// setting, and the "light" and "dark" classes on the html element
+// select a theme manually.
+func HighlightCSS(light, dark string) ([]byte, error) {
+	for _, name := range []string{light, dark} {
+		if err := CheckStyle(name); err != nil {
+			return nil, err
+		}
+	}
+
+	var buf bytes.Buffer
+	for _, t := range []struct{ scheme, style string }{
+		{"light", light},
+		{"dark", dark},
+	} {
+		css, err := styleCSS(t.style)
+		if err != nil {
+			return nil, err
+		}
+
+		fmt.Fprintf(&buf, "/* %s: %s */\n", t.scheme, t.style)
+		fmt.Fprintf(&buf, "@media (prefers-color-scheme: %s) {\n", t.scheme)
+		buf.Write(scopeCSS(css, "  ", ""))
+		buf.WriteString("}\n")
+		buf.Write(scopeCSS(css, "", "html."+t.scheme+" "))
+		buf.WriteString("\n")
+	}
+	return buf.Bytes(), nil
+}
+
+// styleCSS returns the chroma CSS rules for the named style, one rule
+// per line.
+func styleCSS(name string) ([]byte, error) {
+	var buf bytes.Buffer
+	f := chromahtml.New(highlightOptions...)
+	if err := f.WriteCSS(&buf, styles.Get(name)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// scopeCSS indents every rule of css and prefixes its selector with
+// scope. Rules are written by chroma as "/* comment */ selector { }".
+func scopeCSS(css []byte, indent, scope string) []byte {
+	var buf bytes.Buffer
+	scanner := bufio.NewScanner(bytes.NewReader(css))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+		if i := strings.Index(line, "*/ "); i >= 0 {
+			line = line[:i+3] + scope + line[i+3:]
+		}
+		buf.WriteString(indent + line + "\n")
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,73 @@
+package render
+
+import (
+	"html"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+)
+
+// linkAttrPattern matches the link and image URL attributes of
+// rendered HTML.
+var linkAttrPattern = regexp.MustCompile(`\b(href|src)="([^"]*)"`)
+
+// linkTransformer rewrites the relative link and image destinations of
+// a document to absolute paths beneath base, the URL the document is
+// served at, so that they reach the files beside the document.
+type linkTransformer struct {
+	base string
+}
+
+func (t linkTransformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n := n.(type) {
+		case *ast.Link:
+			n.Destination = resolveLink(t.base, n.Destination)
+		case *ast.Image:
+			n.Destination = resolveLink(t.base, n.Destination)
+		}
+		return ast.WalkContinue, nil
+	})
+}
+
+// resolveLink returns dest resolved against base when it is a relative
+// path, and dest unchanged otherwise.
+func resolveLink(base string, dest []byte) []byte {
+	u, err := url.Parse(string(dest))
+	if err != nil || u.Scheme != "" || u.Host != "" || u.Path == "" || strings.HasPrefix(u.Path, "/") {
+		return dest
+	}
+	u.Path = path.Join(base, u.Path)
+	return []byte(u.String())
+}
+
+// FeedContent returns the rendered content of the page served at
+// pageURL, an absolute URL, for feed readers, which cannot resolve
+// relative URLs or style highlighted code: heading anchor links and
+// code line numbers are removed and every link and image URL is made
+// absolute.
+func FeedContent(content []byte, pageURL string) string {
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return string(content)
+	}
+
+	s := lineNumberPattern.ReplaceAllString(string(content), "")
+	s = anchorPattern.ReplaceAllString(s, "")
+	return linkAttrPattern.ReplaceAllStringFunc(s, func(attr string) string {
+		m := linkAttrPattern.FindStringSubmatch(attr)
+		ref, err := url.Parse(html.UnescapeString(m[2]))
+		if err != nil {
+			return attr
+		}
+		return m[1] + `="` + html.EscapeString(base.ResolveReference(ref).String()) + `"`
+	})
+}
@@ -0,0 +1,25 @@
+package render
+
+import (
+	"testing"
+
+	"github.com/ericstrs/site/internal/config"
+)
+
+func TestFeedContent(t *testing.T) {
+	md := "## Intro\n\n[top](#intro) [post](/blogs/other) [mail](mailto:a@b.c) [ext](https://go.dev/?a=1&b=2)\n\n![cover](cover.png)\n\n```go\nfunc main() {}\n```\n"
+	content, _, err := markdownToHTML([]byte(md), "/blogs/hello", config.TOC{MinLevel: 2, MaxLevel: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := FeedContent(content, "https://example.com/blogs/hello")
+	want := `<h2 id="intro">Intro</h2>
+<p><a href="https://example.com/blogs/hello#intro">top</a> <a href="https://example.com/blogs/other">post</a> <a href="mailto:a@b.c">mail</a> <a href="https://go.dev/?a=1&amp;b=2">ext</a></p>
+<p><img src="https://example.com/blogs/hello/cover.png" alt="cover" /></p>
+<pre tabindex="0" class="chroma"><code><span class="line"><span class="cl"><span class="kd">func</span> <span class="nf">main</span><span class="p">()</span> <span class="p">{}</span>
+</span></span></code></pre>`
+	if got != want {
+		t.Errorf("FeedContent() =\n%s\nwant\n%s", got, want)
+	}
+}
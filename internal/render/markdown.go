@@ -3,40 +3,52 @@ package render
 import (
 	"bytes"
 
-	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/ericstrs/site/internal/config"
 	"github.com/yuin/goldmark"
 	highlighting "github.com/yuin/goldmark-highlighting/v2"
 	"github.com/yuin/goldmark/extension"
 	"github.com/yuin/goldmark/parser"
 	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/util"
 )
 
 // markdownToHTML converts the given markdown into its HTML
-// representation
-func markdownToHTML(content []byte) ([]byte, error) {
+// representation, with an anchor link next to each heading. Relative
+// links and images are resolved against base, the URL of the page,
+// unless it is empty. It also returns the table of contents made of the
+// headings within the levels of toc.
+func markdownToHTML(content []byte, base string, toc config.TOC) ([]byte, []Heading, error) {
+	transformers := []util.PrioritizedValue{
+		util.Prioritized(headingTransformer{toc: toc}, 100),
+	}
+	if base != "" {
+		transformers = append(transformers, util.Prioritized(linkTransformer{base: base}, 100))
+	}
+
 	md := goldmark.New(
 		goldmark.WithExtensions(
 			extension.GFM,
 			extension.Footnote,
 			highlighting.NewHighlighting(
-				highlighting.WithStyle("gruvbox"),
-				highlighting.WithFormatOptions(
-					chromahtml.WithLineNumbers(true),
-					chromahtml.WrapLongLines(true),
-				),
+				highlighting.WithFormatOptions(highlightOptions...),
 			),
 		),
 		goldmark.WithParserOptions(
 			parser.WithAutoHeadingID(),
+			parser.WithASTTransformers(transformers...),
 		),
 		goldmark.WithRendererOptions(
 			html.WithHardWraps(),
 			html.WithXHTML(),
 		),
 	)
+
+	pc := parser.NewContext()
 	var buf bytes.Buffer
-	if err := md.Convert(content, &buf); err != nil {
-		return nil, err
+	if err := md.Convert(content, &buf, parser.WithContext(pc)); err != nil {
+		return nil, nil, err
 	}
-	return buf.Bytes(), nil
+
+	headings, _ := pc.Get(headingsKey).([]Heading)
+	return buf.Bytes(), nestHeadings(headings), nil
 }
@@ -1,22 +1,99 @@
 package render
 
-import "os"
+import (
+	"io/fs"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ericstrs/site/internal/config"
+)
+
+// wordsPerMinute is the reading speed used to estimate reading time.
+const wordsPerMinute = 200
+
+// maxDescription is the length in bytes past which a description taken
+// from the first paragraph of a page is truncated.
+const maxDescription = 160
+
+// paragraphPattern matches a paragraph of rendered HTML.
+var paragraphPattern = regexp.MustCompile(`(?s)<p>(.*?)</p>`)
 
 type Page struct {
 	Title   string
 	Content []byte
+	Meta    FrontMatter
+
+	// Description summarizes the page: the front matter summary or
+	// the text of the first paragraph.
+	Description string
+
+	// TOC is the table of contents of the page, empty when disabled by
+	// the front matter.
+	TOC []Heading
+
+	// ReadingTime is the estimated reading time in minutes.
+	ReadingTime int
 }
 
-// LoadPage loads a page
-func LoadPage(title, path string) (*Page, error) {
-	md, err := os.ReadFile(path)
+// loadPage reads, parses and renders the named markdown document of
+// fsys, with a table of contents made of the headings within the
+// levels of toc. Relative links are resolved against base, the URL the
+// page is served at, when it is not empty.
+func loadPage(fsys fs.FS, name, base string, toc config.TOC) (*Page, error) {
+	md, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+
+	meta, body, err := parseDocument(md)
 	if err != nil {
 		return nil, err
 	}
 
-	html, err := markdownToHTML(md)
+	html, headings, err := markdownToHTML(body, base, toc)
 	if err != nil {
 		return nil, err
 	}
-	return &Page{Title: title, Content: html}, nil
+	if meta.TOC != nil && !*meta.TOC {
+		headings = nil
+	}
+	words := len(strings.Fields(plainText(html)))
+	return &Page{
+		Title:       contentTitle(meta, body),
+		Content:     html,
+		Meta:        meta,
+		Description: pageDescription(meta, html),
+		TOC:         headings,
+		ReadingTime: (words + wordsPerMinute - 1) / wordsPerMinute,
+	}, nil
+}
+
+// pageDescription returns the front matter summary, falling back to
+// the text of the first paragraph of the rendered content.
+func pageDescription(meta FrontMatter, content []byte) string {
+	if meta.Summary != "" {
+		return meta.Summary
+	}
+	m := paragraphPattern.FindSubmatch(content)
+	if m == nil {
+		return ""
+	}
+	return truncate(plainText(m[1]), maxDescription)
+}
+
+// truncate shortens s to at most n bytes, cutting at the last space
+// when possible, and marks the cut with an ellipsis.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	cut := strings.LastIndex(s[:n], " ")
+	if cut <= 0 {
+		cut = n
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+	}
+	return strings.TrimRight(s[:cut], " ,.;:") + "…"
 }
@@ -0,0 +1,363 @@
+package render
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ericstrs/site/internal/config"
+	"github.com/ericstrs/site/internal/search"
+)
+
+// Repository holds every markdown document beneath a docs directory,
+// parsed and rendered once, so handlers never touch the disk. It is
+// safe for concurrent use.
+//
+// The docs directory is laid out as top-level pages ("README.md",
+// "about.md"), one directory per configured section with its own
+// "README.md", and one directory per document inside a section
+// ("notes/<id>/README.md").
+type Repository struct {
+	fsys fs.FS
+	opts Options
+
+	mu       sync.RWMutex
+	pages    map[string]*Page
+	sections map[string][]Content // keyed by section name
+	recent   map[string][]Content // keyed by section name, newest first
+	content  map[string]Content   // keyed by "<section name>/<id>"
+	assets   map[string][]string  // keyed by "<section name>/<id>"
+	tags     *taxonomy
+	index    *search.Index
+}
+
+// Options configures what a Repository serves.
+type Options struct {
+	// Drafts makes drafts and documents scheduled for a future date
+	// visible.
+	Drafts bool
+
+	// Sections are the content directories listed by the repository.
+	Sections []config.Section
+
+	// TOC selects the heading levels of the tables of contents.
+	TOC config.TOC
+
+	// Dir is the docs directory on disk, whose git history dates the
+	// documents. It is empty when the docs are not read from disk.
+	Dir string
+}
+
+// NewRepository returns a repository loaded from the docs directory
+// fsys.
+func NewRepository(fsys fs.FS, opts Options) (*Repository, error) {
+	r := &Repository{fsys: fsys, opts: opts}
+	if err := r.Load(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Load scans the docs directory and replaces the repository contents.
+// On error the previous contents are kept.
+func (r *Repository) Load() error {
+	pages := make(map[string]*Page)
+	sections := make(map[string][]Content)
+	content := make(map[string]Content)
+	assets := make(map[string][]string)
+	tags := newTaxonomy()
+	var docs []search.Document
+
+	dates := make(map[string]fileDates)
+	if r.opts.Dir != "" {
+		dates = gitDates(r.opts.Dir)
+	}
+
+	byPath := make(map[string]config.Section)
+	for _, sec := range r.opts.Sections {
+		byPath[sec.Path] = sec
+	}
+
+	err := fs.WalkDir(r.fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		parts := strings.Split(name, "/")
+		sec, inSection := byPath[parts[0]]
+
+		if path.Ext(name) != ".md" {
+			// Files inside a document directory are its assets.
+			if inSection && len(parts) >= 3 && !hidden(parts) {
+				key := sec.Name + "/" + parts[1]
+				assets[key] = append(assets[key], strings.Join(parts[2:], "/"))
+			}
+			return nil
+		}
+
+		document := inSection && len(parts) == 3 && parts[2] == "README.md"
+		var base string
+		if document {
+			base = sec.URL + "/" + parts[1]
+		}
+
+		page, err := loadPage(r.fsys, name, base, r.opts.TOC)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		pages[name] = page
+
+		if document {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			section, id := sec.Name, parts[1]
+			published, updated := contentDates(page.Meta, dates[name], info.ModTime())
+			c := Content{
+				Title:       page.Title,
+				Id:          id,
+				Section:     section,
+				URL:         sec.URL + "/" + id,
+				PublishedAt: published,
+				UpdatedAt:   updated,
+				Meta:        page.Meta,
+			}
+			sections[section] = append(sections[section], c)
+			content[section+"/"+id] = c
+			tags.add(c)
+			docs = append(docs, search.Document{
+				ID:    section + "/" + id,
+				Title: c.Title,
+				Text:  plainText(page.Content),
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	recent := make(map[string][]Content)
+	for _, sec := range r.opts.Sections {
+		recent[sec.Name] = append([]Content(nil), sections[sec.Name]...)
+		sortContent(recent[sec.Name], "date", "desc")
+		sortContent(sections[sec.Name], sec.Sort, sec.Order)
+	}
+
+	tags.sort()
+
+	r.mu.Lock()
+	r.pages = pages
+	r.sections = sections
+	r.recent = recent
+	r.content = content
+	r.assets = assets
+	r.tags = tags
+	r.index = search.NewIndex(docs)
+	r.mu.Unlock()
+
+	return nil
+}
+
+// Page returns the page for the document at the given slash separated
+// path relative to the docs directory, such as "about.md". Hidden
+// documents are not found.
+func (r *Repository) Page(name string) (*Page, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	p, ok := r.pages[name]
+	if !ok || !r.visible(p.Meta) {
+		return nil, false
+	}
+	return p, true
+}
+
+// Document returns the page for the document with the given id in the
+// named section.
+func (r *Repository) Document(section, id string) (*Page, bool) {
+	if id == "" || id != path.Base(id) || id == ".." {
+		return nil, false
+	}
+	for _, sec := range r.opts.Sections {
+		if sec.Name == section {
+			return r.Page(path.Join(sec.Path, id, "README.md"))
+		}
+	}
+	return nil, false
+}
+
+// Content returns the visible content with the given id in the named
+// section.
+func (r *Repository) Content(section, id string) (Content, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	c, ok := r.content[section+"/"+id]
+	if !ok || !r.visible(c.Meta) {
+		return Content{}, false
+	}
+	return c, true
+}
+
+// All returns all the visible content for the named section. The
+// returned slice must not be modified.
+func (r *Repository) All(section string) []Content {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.filter(r.sections[section])
+}
+
+// Recent returns the n most recently published content for the named
+// section, newest first.
+func (r *Repository) Recent(section string, n int) []Content {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var items []Content
+	for _, c := range r.recent[section] {
+		if len(items) == n {
+			break
+		}
+		if r.visible(c.Meta) {
+			items = append(items, c)
+		}
+	}
+	return items
+}
+
+// Adjacent returns the visible content of the named section published
+// just before and just after the content with the given id. Either is
+// nil at the ends of the section.
+func (r *Repository) Adjacent(section, id string) (prev, next *Content) {
+	r.mu.RLock()
+	items := r.filter(r.recent[section])
+	r.mu.RUnlock()
+
+	for i, c := range items {
+		if c.Id != id {
+			continue
+		}
+		if i > 0 {
+			c := items[i-1]
+			next = &c
+		}
+		if i < len(items)-1 {
+			c := items[i+1]
+			prev = &c
+		}
+		break
+	}
+	return prev, next
+}
+
+// Assets returns the files beside the visible document with the given
+// id in the named section, as slash separated paths relative to the
+// document directory. The returned slice must not be modified.
+func (r *Repository) Assets(section, id string) []string {
+	if _, ok := r.Content(section, id); !ok {
+		return nil
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.assets[section+"/"+id]
+}
+
+// OpenAsset opens the named file beside the visible document with the
+// given id in the named section. Only the files listed by Assets can
+// be opened.
+func (r *Repository) OpenAsset(section, id, name string) (fs.File, error) {
+	if !slices.Contains(r.Assets(section, id), name) {
+		return nil, fs.ErrNotExist
+	}
+	for _, sec := range r.opts.Sections {
+		if sec.Name == section {
+			return r.fsys.Open(path.Join(sec.Path, id, name))
+		}
+	}
+	return nil, fs.ErrNotExist
+}
+
+// Layouts returns the template selected by the front matter of every
+// document, visible or not, keyed by URL.
+func (r *Repository) Layouts() map[string]string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	layouts := make(map[string]string)
+	for _, c := range r.content {
+		if c.Meta.Layout != "" {
+			layouts[c.URL] = c.Meta.Layout
+		}
+	}
+	return layouts
+}
+
+// Tags returns every tag used by visible content, ordered by name.
+func (r *Repository) Tags() []Tag {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.tags.list(r.filter)
+}
+
+// Tagged returns the tag with the given slug and the visible content
+// of every section carrying it, ordered by title. The returned slice
+// must not be modified.
+func (r *Repository) Tagged(slug string) (Tag, []Content, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tag, ok := r.tags.tags[slug]
+	if !ok {
+		return Tag{}, nil, false
+	}
+	items := r.filter(r.tags.items[slug])
+	if len(items) == 0 {
+		return Tag{}, nil, false
+	}
+	tag.Count = len(items)
+	return tag, items, true
+}
+
+// visible reports whether a document with the given front matter is
+// served. Scheduled documents become visible once their date passes.
+func (r *Repository) visible(meta FrontMatter) bool {
+	return r.opts.Drafts || meta.Status(time.Now()) == StatusPublished
+}
+
+// hidden reports whether any element of a path starts with a dot.
+func hidden(parts []string) bool {
+	for _, p := range parts {
+		if strings.HasPrefix(p, ".") {
+			return true
+		}
+	}
+	return false
+}
+
+// filter returns the visible content of items.
+func (r *Repository) filter(items []Content) []Content {
+	if r.opts.Drafts {
+		return items
+	}
+
+	visible := make([]Content, 0, len(items))
+	for _, c := range items {
+		if r.visible(c.Meta) {
+			visible = append(visible, c)
+		}
+	}
+	return visible
+}
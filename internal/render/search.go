@@ -0,0 +1,53 @@
+package render
+
+import (
+	"html"
+	"html/template"
+	"regexp"
+	"strings"
+)
+
+var (
+	// tagPattern matches an HTML tag.
+	tagPattern = regexp.MustCompile(`<[^>]*>`)
+
+	// anchorPattern matches the anchor link added to each heading.
+	anchorPattern = regexp.MustCompile(`<a href="[^"]*" class="anchor">¶</a>`)
+
+	// lineNumberPattern matches the line number spans chroma adds to
+	// highlighted code blocks, which are styled by class.
+	lineNumberPattern = regexp.MustCompile(`<span class="ln">[^<]*</span>`)
+)
+
+// SearchResult is content matching a search query.
+type SearchResult struct {
+	Content
+	Snippet template.HTML
+}
+
+// Search returns the visible content matching query, best match first.
+// Words must all appear in a document; quoted words must appear as a
+// phrase.
+func (r *Repository) Search(query string) []SearchResult {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var results []SearchResult
+	for _, hit := range r.index.Search(query) {
+		c, ok := r.content[hit.ID]
+		if !ok || !r.visible(c.Meta) {
+			continue
+		}
+		results = append(results, SearchResult{Content: c, Snippet: hit.Snippet})
+	}
+	return results
+}
+
+// plainText returns the text of the rendered HTML with tags removed
+// and whitespace collapsed.
+func plainText(content []byte) string {
+	s := lineNumberPattern.ReplaceAllString(string(content), "")
+	s = anchorPattern.ReplaceAllString(s, "")
+	s = tagPattern.ReplaceAllString(s, " ")
+	return strings.Join(strings.Fields(html.UnescapeString(s)), " ")
+}
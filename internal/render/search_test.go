@@ -0,0 +1,31 @@
+package render
+
+import (
+	"testing"
+
+	"github.com/ericstrs/site/internal/config"
+)
+
+func TestPlainText(t *testing.T) {
+	toc := config.TOC{MinLevel: config.DefaultTOCMinLevel, MaxLevel: config.DefaultTOCMaxLevel}
+	tests := []struct {
+		name string
+		md   string
+		want string
+	}{
+		{"paragraph", "Some *emphasized* text &amp; more.\n", "Some emphasized text & more."},
+		{"heading anchors", "# Title\n\n## Section\n\nBody.\n", "Title Section Body."},
+		{"highlighted code", "Intro\n\n```go\nfunc main() {}\nreturn\n```\n", "Intro func main () {} return"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			html, _, err := markdownToHTML([]byte(tt.md), "", toc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := plainText(html); got != tt.want {
+				t.Errorf("plainText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package render
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Tag is a front matter tag and the number of documents carrying it.
+type Tag struct {
+	Name  string
+	Slug  string
+	Count int
+}
+
+// taxonomy indexes the content of every section by tag slug.
+type taxonomy struct {
+	tags  map[string]Tag
+	items map[string][]Content
+}
+
+func newTaxonomy() *taxonomy {
+	return &taxonomy{
+		tags:  make(map[string]Tag),
+		items: make(map[string][]Content),
+	}
+}
+
+// add indexes c under each of its tags.
+func (t *taxonomy) add(c Content) {
+	for _, tag := range NewTags(c.Meta.Tags) {
+		if _, ok := t.tags[tag.Slug]; !ok {
+			t.tags[tag.Slug] = tag
+		}
+		t.items[tag.Slug] = append(t.items[tag.Slug], c)
+	}
+}
+
+// sort orders the content of every tag by title.
+func (t *taxonomy) sort() {
+	for _, items := range t.items {
+		sort.SliceStable(items, func(i, j int) bool {
+			return items[i].Title < items[j].Title
+		})
+	}
+}
+
+// list returns every tag carried by at least one visible content,
+// ordered by name, with Count set to the number of visible content.
+func (t *taxonomy) list(visible func([]Content) []Content) []Tag {
+	tags := make([]Tag, 0, len(t.tags))
+	for slug, tag := range t.tags {
+		tag.Count = len(visible(t.items[slug]))
+		if tag.Count == 0 {
+			continue
+		}
+		tags = append(tags, tag)
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		return tags[i].Slug < tags[j].Slug
+	})
+	return tags
+}
+
+// NewTags returns the tags for the given front matter tag names,
+// dropping empty and duplicate names.
+func NewTags(names []string) []Tag {
+	var tags []Tag
+	seen := make(map[string]bool)
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		slug := TagSlug(name)
+		if slug == "" || seen[slug] {
+			continue
+		}
+		seen[slug] = true
+		tags = append(tags, Tag{Name: name, Slug: slug})
+	}
+	return tags
+}
+
+// TagSlug returns the URL path segment for the tag name: lower case
+// letters and digits separated by hyphens.
+func TagSlug(name string) string {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if hyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			hyphen = false
+			b.WriteRune(r)
+		default:
+			hyphen = true
+		}
+	}
+	return b.String()
+}
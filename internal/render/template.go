@@ -3,29 +3,65 @@ package render
 import (
 	"bytes"
 	"html/template"
+	"io/fs"
+	"sync/atomic"
 )
 
 var (
-	tmplDir    = "public/templates"
-	headPath   = tmplDir + "/head.tmpl"
-	headerPath = tmplDir + "/header.tmpl"
-	footerPath = tmplDir + "/footer.tmpl"
-	homePath   = tmplDir + "/home.html" // path to the home html page
-	nfPath     = tmplDir + "/notfound.html"
-	aboutPath  = tmplDir + "/about.html"
-	notesPath  = tmplDir + "/notes.html"
-	notePath   = tmplDir + "/note.html"
-	blogsPath  = tmplDir + "/blogs.html"
-	blogPath   = tmplDir + "/blog.html"
-	templates  = template.Must(template.ParseFS(Public, headPath,
-		headerPath, footerPath, homePath, nfPath, aboutPath, notesPath,
-		notePath, blogsPath, blogPath))
+	tmplDir      = "public/templates"
+	tmplPatterns = []string{"*.tmpl", "*.html"}
+
+	devMode   atomic.Bool
+	templates atomic.Pointer[template.Template]
 )
 
+func init() {
+	fsys, err := fs.Sub(Public, tmplDir)
+	if err != nil {
+		panic(err)
+	}
+	if err := LoadTemplates(fsys); err != nil {
+		panic(err)
+	}
+}
+
+// LoadTemplates parses every .tmpl and .html template at the root of
+// fsys and replaces the ones used by Template. On error the previous
+// templates are kept.
+func LoadTemplates(fsys fs.FS) error {
+	funcs := template.FuncMap{
+		"devMode": devMode.Load,
+	}
+	t, err := template.New("").Funcs(funcs).ParseFS(fsys, tmplPatterns...)
+	if err != nil {
+		return err
+	}
+	templates.Store(t)
+	return nil
+}
+
+// SaveTemplates returns a function that restores the templates used
+// by Template to the current ones.
+func SaveTemplates() (restore func()) {
+	t := templates.Load()
+	return func() { templates.Store(t) }
+}
+
+// SetDevMode sets whether rendered pages include the development
+// live reload script.
+func SetDevMode(on bool) {
+	devMode.Store(on)
+}
+
+// HasTemplate reports whether the named HTML template exists.
+func HasTemplate(tmpl string) bool {
+	return templates.Load().Lookup(tmpl+".html") != nil
+}
+
 // Template renders the specified HTML template and returns it
 func Template(tmpl string, data any) ([]byte, error) {
 	buff := new(bytes.Buffer)
-	if err := templates.ExecuteTemplate(buff, tmpl+".html", data); err != nil {
+	if err := templates.Load().ExecuteTemplate(buff, tmpl+".html", data); err != nil {
 		return []byte{}, err
 	}
 	return buff.Bytes(), nil
@@ -0,0 +1,84 @@
+package render
+
+import (
+	"github.com/ericstrs/site/internal/config"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+)
+
+// Heading is an entry of the table of contents of a page.
+type Heading struct {
+	Level    int
+	ID       string
+	Title    string
+	Children []Heading
+}
+
+// headingsKey holds the headings collected by headingTransformer in
+// the parser context.
+var headingsKey = parser.NewContextKey()
+
+// headingTransformer collects the headings of a document within the
+// configured levels and appends a "¶" anchor link to every heading.
+type headingTransformer struct {
+	toc config.TOC
+}
+
+func (t headingTransformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	var headings []Heading
+	source := reader.Source()
+
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		h, ok := n.(*ast.Heading)
+		if !entering || !ok {
+			return ast.WalkContinue, nil
+		}
+
+		id, ok := h.AttributeString("id")
+		if !ok {
+			return ast.WalkSkipChildren, nil
+		}
+		idStr := string(id.([]byte))
+
+		if h.Level >= t.toc.MinLevel && h.Level <= t.toc.MaxLevel {
+			headings = append(headings, Heading{
+				Level: h.Level,
+				ID:    idStr,
+				Title: string(h.Text(source)),
+			})
+		}
+
+		anchor := ast.NewLink()
+		anchor.Destination = []byte("#" + idStr)
+		anchor.SetAttributeString("class", []byte("anchor"))
+		anchor.AppendChild(anchor, ast.NewString([]byte("¶")))
+		h.AppendChild(h, anchor)
+
+		return ast.WalkSkipChildren, nil
+	})
+
+	pc.Set(headingsKey, headings)
+}
+
+// nestHeadings arranges a flat list of headings into a tree, each
+// heading holding the deeper headings that follow it.
+func nestHeadings(flat []Heading) []Heading {
+	var nest func(level int) []Heading
+	nest = func(level int) []Heading {
+		var out []Heading
+		for len(flat) > 0 && flat[0].Level >= level {
+			h := flat[0]
+			flat = flat[1:]
+			h.Children = nest(h.Level + 1)
+			out = append(out, h)
+		}
+		return out
+	}
+
+	var toc []Heading
+	for len(flat) > 0 {
+		toc = append(toc, nest(flat[0].Level)...)
+	}
+	return toc
+}
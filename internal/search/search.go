@@ -0,0 +1,284 @@
+package search
+
+import (
+	"html"
+	"html/template"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	// titleBoost weighs a match in the title against one in the body.
+	titleBoost = 3.0
+
+	// snippetBefore and snippetAfter are the number of words shown
+	// around the first match in a snippet.
+	snippetBefore = 12
+	snippetAfter  = 24
+)
+
+// Document is a unit of searchable text.
+type Document struct {
+	ID    string
+	Title string
+	Text  string
+}
+
+// Hit is a document matching a query.
+type Hit struct {
+	ID      string
+	Score   float64
+	Snippet template.HTML // body excerpt with matches wrapped in <mark>
+}
+
+// Index is an inverted index over the title and text of documents. It
+// is immutable once built and safe for concurrent use.
+type Index struct {
+	docs     []indexedDoc
+	postings map[string]map[int][]int // term -> document -> positions
+}
+
+// indexedDoc holds what is needed to build snippets for a document.
+// Title words take positions [0, titleLen) and body word i takes
+// position titleLen+1+i, so phrases never span title and body.
+type indexedDoc struct {
+	id       string
+	titleLen int
+	text     string
+	words    []word
+}
+
+// word is a term and its byte offsets in the text it was read from.
+type word struct {
+	term       string
+	start, end int
+}
+
+// NewIndex returns an index of the given documents.
+func NewIndex(docs []Document) *Index {
+	ix := &Index{postings: make(map[string]map[int][]int)}
+
+	for i, d := range docs {
+		title := tokenize(d.Title)
+		body := tokenize(d.Text)
+
+		for pos, w := range title {
+			ix.add(w.term, i, pos)
+		}
+		for n, w := range body {
+			ix.add(w.term, i, len(title)+1+n)
+		}
+
+		ix.docs = append(ix.docs, indexedDoc{
+			id:       d.ID,
+			titleLen: len(title),
+			text:     d.Text,
+			words:    body,
+		})
+	}
+
+	return ix
+}
+
+func (ix *Index) add(term string, doc, pos int) {
+	if ix.postings[term] == nil {
+		ix.postings[term] = make(map[int][]int)
+	}
+	ix.postings[term][doc] = append(ix.postings[term][doc], pos)
+}
+
+// Search returns the documents matching every word and quoted phrase
+// of query, best match first. Matches are ranked by term frequency
+// weighted by inverse document frequency, with title matches boosted.
+func (ix *Index) Search(query string) []Hit {
+	clauses := parseQuery(query)
+	if len(clauses) == 0 || len(ix.docs) == 0 {
+		return nil
+	}
+
+	// matches maps each document to the start positions of every
+	// clause, in clause order.
+	matches := make(map[int][][]int)
+	for i, c := range clauses {
+		occurrences := ix.match(c)
+		if len(occurrences) == 0 {
+			return nil
+		}
+		for doc, positions := range occurrences {
+			if len(matches[doc]) == i {
+				matches[doc] = append(matches[doc], positions)
+			}
+		}
+		for doc, found := range matches {
+			if len(found) != i+1 {
+				delete(matches, doc)
+			}
+		}
+	}
+
+	idf := make([]float64, len(clauses))
+	for i, c := range clauses {
+		df := len(ix.match(c))
+		idf[i] = math.Log(1 + float64(len(ix.docs))/float64(df))
+	}
+
+	terms := make(map[string]bool)
+	for _, c := range clauses {
+		for _, t := range c {
+			terms[t] = true
+		}
+	}
+
+	hits := make([]Hit, 0, len(matches))
+	for doc, found := range matches {
+		d := ix.docs[doc]
+
+		var score float64
+		first := -1
+		for i, positions := range found {
+			for _, pos := range positions {
+				if pos < d.titleLen {
+					score += titleBoost * idf[i]
+					continue
+				}
+				score += idf[i]
+				if n := pos - d.titleLen - 1; first == -1 || n < first {
+					first = n
+				}
+			}
+		}
+
+		hits = append(hits, Hit{
+			ID:      d.id,
+			Score:   score,
+			Snippet: d.snippet(first, terms),
+		})
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].ID < hits[j].ID
+	})
+
+	return hits
+}
+
+// match returns the start positions of the clause in every document
+// containing it. A clause of several terms is a phrase whose terms
+// must appear at consecutive positions.
+func (ix *Index) match(c clause) map[int][]int {
+	first := ix.postings[c[0]]
+	if len(c) == 1 {
+		return first
+	}
+
+	found := make(map[int][]int)
+	for doc, positions := range first {
+	next:
+		for _, pos := range positions {
+			for k, term := range c[1:] {
+				if !contains(ix.postings[term][doc], pos+k+1) {
+					continue next
+				}
+			}
+			found[doc] = append(found[doc], pos)
+		}
+	}
+	return found
+}
+
+// snippet returns an excerpt of the body around the word at index
+// first, with the given terms highlighted. A negative first returns
+// the start of the body.
+func (d indexedDoc) snippet(first int, terms map[string]bool) template.HTML {
+	if len(d.words) == 0 {
+		return ""
+	}
+
+	start := max(first-snippetBefore, 0)
+	end := min(max(first, 0)+snippetAfter, len(d.words))
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("… ")
+	}
+
+	offset := d.words[start].start
+	for _, w := range d.words[start:end] {
+		b.WriteString(html.EscapeString(d.text[offset:w.start]))
+		text := html.EscapeString(d.text[w.start:w.end])
+		if terms[w.term] {
+			b.WriteString("<mark>" + text + "</mark>")
+		} else {
+			b.WriteString(text)
+		}
+		offset = w.end
+	}
+
+	if end < len(d.words) {
+		b.WriteString(" …")
+	} else {
+		b.WriteString(html.EscapeString(d.text[offset:]))
+	}
+
+	return template.HTML(b.String())
+}
+
+// clause is a query term, or a phrase when it holds several terms.
+type clause []string
+
+// parseQuery splits query into clauses: one per quoted phrase and one
+// per word outside quotes.
+func parseQuery(query string) []clause {
+	var clauses []clause
+	for i, part := range strings.Split(query, `"`) {
+		words := tokenize(part)
+		if len(words) == 0 {
+			continue
+		}
+
+		// Odd parts are inside quotes.
+		if i%2 == 1 {
+			phrase := make(clause, len(words))
+			for n, w := range words {
+				phrase[n] = w.term
+			}
+			clauses = append(clauses, phrase)
+			continue
+		}
+		for _, w := range words {
+			clauses = append(clauses, clause{w.term})
+		}
+	}
+	return clauses
+}
+
+// tokenize splits s into lower case words of letters and digits.
+func tokenize(s string) []word {
+	var words []word
+	start := -1
+	for i, r := range s {
+		isWord := unicode.IsLetter(r) || unicode.IsDigit(r)
+		switch {
+		case isWord && start == -1:
+			start = i
+		case !isWord && start != -1:
+			words = append(words, word{strings.ToLower(s[start:i]), start, i})
+			start = -1
+		}
+	}
+	if start != -1 {
+		words = append(words, word{strings.ToLower(s[start:]), start, len(s)})
+	}
+	return words
+}
+
+// contains reports whether the sorted positions include pos.
+func contains(positions []int, pos int) bool {
+	i := sort.SearchInts(positions, pos)
+	return i < len(positions) && positions[i] == pos
+}
@@ -0,0 +1,109 @@
+package search
+
+import (
+	"html/template"
+	"testing"
+)
+
+// ids returns the document IDs of hits, in order.
+func ids(hits []Hit) []string {
+	var ids []string
+	for _, h := range hits {
+		ids = append(ids, h.ID)
+	}
+	return ids
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestSearch(t *testing.T) {
+	ix := NewIndex([]Document{
+		{ID: "go", Title: "Hello world", Text: "Again and again, Go programs."},
+		{ID: "rust", Title: "Rust notes", Text: "Hello from Rust. World peace."},
+		{ID: "common", Title: "Common words", Text: "Hello hello world."},
+	})
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{"word", "rust", []string{"rust"}},
+		{"case insensitive", "GO", []string{"go"}},
+		{"every word", "hello rust", []string{"rust"}},
+		{"missing word", "hello python", nil},
+		{"phrase", `"hello world"`, []string{"go", "common"}},
+		{"phrase order", `"world hello"`, nil},
+		{"phrase across title and body", `"world again"`, nil},
+		{"phrase and word", `"hello world" programs`, []string{"go"}},
+		{"empty", ` "" `, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ids(ix.Search(tt.query)); !equal(got, tt.want) {
+				t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchRanking(t *testing.T) {
+	ix := NewIndex([]Document{
+		{ID: "a", Title: "One", Text: "common common"},
+		{ID: "b", Title: "Two", Text: "common common common common"},
+		{ID: "c", Title: "Three", Text: "common rare"},
+		{ID: "d", Title: "Common", Text: "nothing else"},
+		{ID: "e", Title: "Five", Text: "rare"},
+	})
+
+	// Term frequency ranks documents, and a title match counts more
+	// than a body match.
+	if got, want := ids(ix.Search("common")), []string{"b", "d", "a", "c"}; !equal(got, want) {
+		t.Errorf("Search(common) = %v, want %v", got, want)
+	}
+
+	// A term in fewer documents weighs more.
+	common, rare := ix.Search("common"), ix.Search("rare")
+	if common[len(common)-1].Score >= rare[0].Score {
+		t.Errorf("one match of common scored %v, of rare %v: want rare higher",
+			common[len(common)-1].Score, rare[0].Score)
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	ix := NewIndex([]Document{
+		{ID: "html", Title: "Escaping", Text: `Use <script> & "quotes" in Go code.`},
+	})
+
+	hits := ix.Search("script")
+	if len(hits) != 1 {
+		t.Fatalf("Search() = %v, want one hit", hits)
+	}
+	want := template.HTML(`Use &lt;<mark>script</mark>&gt; &amp; &#34;quotes&#34; in Go code.`)
+	if hits[0].Snippet != want {
+		t.Errorf("snippet = %q, want %q", hits[0].Snippet, want)
+	}
+}
+
+func TestSnippetWindow(t *testing.T) {
+	text := "w0 w1 w2 w3 w4 w5 w6 w7 w8 w9 w10 w11 w12 w13 w14 w15 w16 w17 w18 w19 " +
+		"w20 w21 w22 w23 w24 w25 w26 w27 w28 w29 w30 w31 w32 w33 w34 w35 w36 w37 w38 w39 w40"
+	ix := NewIndex([]Document{{ID: "long", Text: text}})
+
+	hits := ix.Search("w14")
+	want := template.HTML("… w2 w3 w4 w5 w6 w7 w8 w9 w10 w11 w12 w13 <mark>w14</mark> w15 w16 w17 w18 w19 w20 " +
+		"w21 w22 w23 w24 w25 w26 w27 w28 w29 w30 w31 w32 w33 w34 w35 w36 w37 …")
+	if len(hits) != 1 || hits[0].Snippet != want {
+		t.Errorf("snippet = %q, want %q", hits[0].Snippet, want)
+	}
+}
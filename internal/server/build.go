@@ -0,0 +1,39 @@
+package server
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/ericstrs/site/internal/config"
+	"github.com/ericstrs/site/internal/export"
+)
+
+// Build exports every page of the site as a static tree rooted at
+// dir, rendered by the same handlers Serve uses.
+func Build(cfg *config.Config, dir string) error {
+	opts := &slog.HandlerOptions{Level: slog.LevelWarn}
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, opts)))
+
+	assets, err := loadTheme(cfg, "")
+	if err != nil {
+		return err
+	}
+
+	repo, err := newRepository(cfg, false)
+	if err != nil {
+		return fmt.Errorf("failed to load content: %w", err)
+	}
+
+	handler, err := newHandler(cfg, repo, assets)
+	if err != nil {
+		return err
+	}
+
+	paths, files, err := pages(cfg, repo, assets)
+	if err != nil {
+		return fmt.Errorf("failed to collect pages: %w", err)
+	}
+
+	return export.Write(handler, paths, files, dir)
+}
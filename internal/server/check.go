@@ -0,0 +1,24 @@
+package server
+
+import (
+	"github.com/ericstrs/site/internal/config"
+	"github.com/ericstrs/site/internal/render"
+)
+
+// Check loads the theme of cfg, with the templates of templatesDir
+// when it is not empty, and returns the problems that would stop the
+// site from being served: missing section templates and documents
+// that fail render.Check.
+func Check(cfg *config.Config, templatesDir string) []error {
+	if _, err := loadTheme(cfg, templatesDir); err != nil {
+		return []error{err}
+	}
+
+	var errs []error
+	for _, sec := range cfg.Sections {
+		if err := checkTemplates(sec); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return append(errs, render.Check(cfg.Docs())...)
+}
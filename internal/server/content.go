@@ -0,0 +1,23 @@
+package server
+
+import (
+	"github.com/ericstrs/site/internal/config"
+	"github.com/ericstrs/site/internal/render"
+)
+
+// newRepository loads the content of the configured docs directory,
+// including drafts and scheduled documents when drafts is true.
+func newRepository(cfg *config.Config, drafts bool) (*render.Repository, error) {
+	// Only content on disk has a git history.
+	var dir string
+	if cfg.DocsFS == nil {
+		dir = cfg.DocsPath
+	}
+
+	return render.NewRepository(cfg.Docs(), render.Options{
+		Drafts:   drafts,
+		Sections: cfg.Sections,
+		TOC:      cfg.TOC,
+		Dir:      dir,
+	})
+}
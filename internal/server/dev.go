@@ -0,0 +1,147 @@
+package server
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ericstrs/site/internal/render"
+)
+
+// reloadScript is served at /_dev/reload.js and included by head.tmpl
+// in dev mode. It reloads the page when the server sends a reload
+// event.
+const reloadScript = `new EventSource("/_dev/events").addEventListener("reload", () => location.reload());
+`
+
+// devServer serves the site in dev mode. It rebuilds the site handler
+// whenever content, config or templates change and pushes a reload
+// event to every open browser over server-sent events.
+type devServer struct {
+	opts    Options
+	handler atomic.Value // http.Handler
+
+	mu      sync.Mutex
+	clients map[chan struct{}]struct{}
+	done    chan struct{}
+}
+
+// newDevServer returns a dev server initially serving handler.
+func newDevServer(opts Options, handler http.Handler) *devServer {
+	d := &devServer{
+		opts:    opts,
+		clients: make(map[chan struct{}]struct{}),
+		done:    make(chan struct{}),
+	}
+	d.handler.Store(handler)
+	return d
+}
+
+func (d *devServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/_dev/events":
+		d.events(w, r)
+	case "/_dev/reload.js":
+		w.Header().Set("Content-Type", "text/javascript; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		fmt.Fprint(w, reloadScript)
+	default:
+		d.handler.Load().(http.Handler).ServeHTTP(w, r)
+	}
+}
+
+// reload rebuilds the config, theme, content and site handler,
+// then notifies the open browsers. On error the current handler and
+// its templates are kept.
+func (d *devServer) reload() {
+	cfg, err := d.opts.LoadConfig()
+	if err != nil {
+		slog.Error("Failed to reload config", "err", err)
+		return
+	}
+
+	// loadTheme replaces the templates the current handler renders
+	// with, so they are restored when the reload fails.
+	restore := render.SaveTemplates()
+	assets, err := loadTheme(cfg, d.opts.Templates)
+	if err != nil {
+		restore()
+		slog.Error("Failed to reload theme", "err", err)
+		return
+	}
+
+	repo, err := newRepository(cfg, d.opts.Drafts)
+	if err != nil {
+		restore()
+		slog.Error("Failed to reload content", "err", err)
+		return
+	}
+
+	handler, err := newHandler(cfg, repo, assets)
+	if err != nil {
+		restore()
+		slog.Error("Failed to rebuild handler", "err", err)
+		return
+	}
+	d.handler.Store(handler)
+
+	slog.Info("Reloaded site")
+	d.broadcast()
+}
+
+// events streams a reload event to the browser after every reload.
+func (d *devServer) events(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ch := make(chan struct{}, 1)
+	d.mu.Lock()
+	d.clients[ch] = struct{}{}
+	d.mu.Unlock()
+
+	defer func() {
+		d.mu.Lock()
+		delete(d.clients, ch)
+		d.mu.Unlock()
+	}()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-d.done:
+			return
+		case <-ch:
+			fmt.Fprint(w, "event: reload\ndata: {}\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+// broadcast notifies every connected browser to reload.
+func (d *devServer) broadcast() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for ch := range d.clients {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// close ends every open event stream so the server can shut down.
+func (d *devServer) close() {
+	close(d.done)
+}
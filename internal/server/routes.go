@@ -0,0 +1,136 @@
+package server
+
+import (
+	"fmt"
+	"io/fs"
+	"net/http"
+	"sort"
+
+	"github.com/ericstrs/site/internal/config"
+	"github.com/ericstrs/site/internal/export"
+	"github.com/ericstrs/site/internal/handlers"
+	"github.com/ericstrs/site/internal/middleware"
+	"github.com/ericstrs/site/internal/render"
+)
+
+// reservedPaths are routed by newHandler and cannot be used as a
+// section url.
+var reservedPaths = map[string]bool{
+	"/about":       true,
+	"/tags":        true,
+	"/search":      true,
+	"/search.json": true,
+	"/feed.xml":    true,
+	"/atom.xml":    true,
+	"/sitemap.xml": true,
+	"/robots.txt":  true,
+}
+
+// newHandler returns the site handler with every route and middleware
+// registered. Requests for other paths are served from assets.
+func newHandler(cfg *config.Config, repo *render.Repository, assets fs.FS) (http.Handler, error) {
+	mux := http.NewServeMux()
+	mux.Handle("GET /{$}", middleware.LogRequest(handlers.Home(cfg, repo)))
+	mux.Handle("GET /about", middleware.LogRequest(handlers.About(cfg, repo)))
+	mux.Handle("GET /tags", middleware.LogRequest(handlers.Tags(cfg, repo)))
+	mux.Handle("GET /tags/{tag}", middleware.LogRequest(handlers.Tag(cfg, repo)))
+	mux.Handle("GET /search", middleware.LogRequest(handlers.Search(cfg, repo)))
+	mux.Handle("GET /search.json", middleware.LogRequest(handlers.SearchJSON(cfg, repo)))
+	mux.Handle("GET /sitemap.xml", middleware.LogRequest(handlers.Sitemap(cfg, repo)))
+	mux.Handle("GET /robots.txt", middleware.LogRequest(handlers.Robots(cfg)))
+
+	chromaCSS, err := render.HighlightCSS(cfg.Syntax.LightMode.Theme, cfg.Syntax.DarkMode.Theme)
+	if err != nil {
+		return nil, err
+	}
+	mux.Handle("GET /css/chroma.css", middleware.LogRequest(handlers.Stylesheet(chromaCSS)))
+
+	var names []string
+	for _, sec := range cfg.Sections {
+		names = append(names, sec.Name)
+	}
+	mux.Handle("GET /feed.xml", middleware.LogRequest(handlers.Feed(cfg, repo, "rss", names...)))
+	mux.Handle("GET /atom.xml", middleware.LogRequest(handlers.Feed(cfg, repo, "atom", names...)))
+
+	for _, sec := range cfg.Sections {
+		if reservedPaths[sec.URL] {
+			return nil, fmt.Errorf("section %q: url %q is reserved", sec.Name, sec.URL)
+		}
+		if err := checkTemplates(sec); err != nil {
+			return nil, err
+		}
+
+		mux.Handle("GET "+sec.URL, middleware.LogRequest(handlers.Section(cfg, repo, sec)))
+		mux.Handle("GET "+sec.URL+"/page/{n}", middleware.LogRequest(handlers.Section(cfg, repo, sec)))
+		mux.Handle("GET "+sec.URL+"/{id}", middleware.LogRequest(handlers.Document(cfg, repo, sec)))
+		mux.Handle("GET "+sec.URL+"/{id}/{file...}", middleware.LogRequest(handlers.Asset(cfg, repo, sec)))
+		mux.Handle("GET "+sec.URL+"/feed.xml", middleware.LogRequest(handlers.Feed(cfg, repo, "rss", sec.Name)))
+		mux.Handle("GET "+sec.URL+"/atom.xml", middleware.LogRequest(handlers.Feed(cfg, repo, "atom", sec.Name)))
+	}
+
+	layouts := repo.Layouts()
+	urls := make([]string, 0, len(layouts))
+	for url := range layouts {
+		urls = append(urls, url)
+	}
+	sort.Strings(urls)
+	for _, url := range urls {
+		if !render.HasTemplate(layouts[url]) {
+			return nil, fmt.Errorf("%s: layout %q not found", url, layouts[url])
+		}
+	}
+
+	mux.Handle("GET /", http.FileServer(http.FS(assets)))
+
+	handler := middleware.SecurityHeaders(cfg, mux)
+	handler = middleware.Errors(handlers.Error(cfg), handler)
+	handler = middleware.PanicRecovery(handlers.Error(cfg), handler)
+
+	return handler, nil
+}
+
+// checkTemplates reports whether the templates of sec are loaded.
+func checkTemplates(sec config.Section) error {
+	for _, tmpl := range []string{sec.Template, sec.ListTemplate} {
+		if !render.HasTemplate(tmpl) {
+			return fmt.Errorf("section %q: template %q not found", sec.Name, tmpl)
+		}
+	}
+	return nil
+}
+
+// pages returns the path of every page routed by newHandler, and
+// separately the path of every file served as is: the static assets
+// and the files beside documents.
+func pages(cfg *config.Config, repo *render.Repository, assets fs.FS) (paths, files []string, err error) {
+	paths = []string{"/", "/about", "/tags", "/feed.xml", "/atom.xml", "/sitemap.xml", "/robots.txt", "/css/chroma.css"}
+
+	for _, tag := range repo.Tags() {
+		paths = append(paths, "/tags/"+tag.Slug)
+	}
+
+	for _, sec := range cfg.Sections {
+		paths = append(paths, sec.URL, sec.URL+"/feed.xml", sec.URL+"/atom.xml")
+		items := repo.All(sec.Name)
+		for n := 2; n <= handlers.PageCount(sec, len(items)); n++ {
+			paths = append(paths, handlers.PageURL(sec, n))
+		}
+		for _, item := range items {
+			paths = append(paths, item.URL)
+			for _, file := range repo.Assets(sec.Name, item.Id) {
+				// The page itself is exported as index.html.
+				if file == "index.html" {
+					return nil, nil, fmt.Errorf("%s/%s: file would replace the exported page", item.URL, file)
+				}
+				files = append(files, item.URL+"/"+file)
+			}
+		}
+	}
+
+	static, err := export.Assets(assets, "templates")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return paths, append(files, static...), nil
+}
@@ -3,24 +3,47 @@ package server
 import (
 	"context"
 	"errors"
-	"io/fs"
 	"log"
 	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"runtime/debug"
 	"strconv"
 	"syscall"
 	"time"
 
 	"github.com/ericstrs/site/internal/config"
-	"github.com/ericstrs/site/internal/handlers"
 	"github.com/ericstrs/site/internal/middleware"
 	"github.com/ericstrs/site/internal/render"
+	"github.com/ericstrs/site/internal/watch"
 )
 
-func Serve() {
+// Options configures optional server behaviour.
+type Options struct {
+	// Dev enables live reload: the docs directory, the config file, the
+	// theme directory and the template directory are watched, the site
+	// is rebuilt on change and open browsers are told to reload.
+	Dev bool
+
+	// Drafts serves drafts and scheduled documents with a banner.
+	Drafts bool
+
+	// ConfigPath is the config file watched in dev mode.
+	ConfigPath string
+
+	// Templates is an on-disk template directory whose templates
+	// override the ones of the theme.
+	Templates string
+
+	// LoadConfig reloads the config in dev mode.
+	LoadConfig func() (*config.Config, error)
+}
+
+// Serve runs the site server with the given config until it receives
+// a termination signal.
+func Serve(cfg *config.Config, o Options) {
 	var trace = string(debug.Stack())
 	var logLevel = new(slog.LevelVar)
 
@@ -34,28 +57,30 @@ func Serve() {
 
 	logLevel.Set(slog.LevelInfo)
 
-	cfg, err := config.LoadConfig()
+	assets, err := loadTheme(cfg, o.Templates)
 	if err != nil {
-		slog.Error("Server failed", "err", err, "trace", trace)
+		slog.Error("Failed to load theme", "err", err, "trace", trace)
 		os.Exit(1)
 	}
+	render.SetDevMode(o.Dev)
 
-	mux := http.NewServeMux()
-	mux.Handle("GET /{$}", middleware.LogRequest(handlers.Home(cfg)))
-	mux.Handle("GET /about", middleware.LogRequest(handlers.About(cfg)))
-	mux.Handle("GET /notes", middleware.LogRequest(handlers.Notes(cfg)))
-	mux.Handle("GET /notes/{id}", middleware.LogRequest(handlers.Note(cfg)))
-	mux.Handle("GET /blogs", middleware.LogRequest(handlers.Blogs(cfg)))
-	mux.Handle("GET /blogs/{id}", middleware.LogRequest(handlers.Blog(cfg)))
+	repo, err := newRepository(cfg, o.Drafts)
+	if err != nil {
+		slog.Error("Failed to load content", "err", err, "trace", trace)
+		os.Exit(1)
+	}
 
-	publicFS, err := fs.Sub(render.Public, "public")
+	handler, err := newHandler(cfg, repo, assets)
 	if err != nil {
 		log.Fatal(err)
 	}
-	mux.Handle("/", http.FileServer(http.FS(publicFS)))
 
-	handler := middleware.SecurityHeaders(cfg, mux)
-	handler = middleware.PanicRecovery(handler)
+	var dev *devServer
+	if o.Dev {
+		dev = newDevServer(o, handler)
+		handler = dev
+	}
+	handler = middleware.RequestID(handler)
 
 	portStr := strconv.Itoa(cfg.Port)
 	addr := cfg.Host + ":" + portStr
@@ -64,6 +89,19 @@ func Serve() {
 		Handler: handler,
 	}
 
+	if dev != nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		paths := []string{cfg.DocsPath, o.ConfigPath, filepath.Join(cfg.ThemesPath, cfg.Theme)}
+		if o.Templates != "" {
+			paths = append(paths, o.Templates)
+		}
+		go watch.Poll(ctx, 500*time.Millisecond, paths, dev.reload)
+		srv.RegisterOnShutdown(dev.close)
+		logger.Info("Watching for changes", "paths", paths)
+	}
+
 	go func() {
 		logger.Info("Server is starting...", "addr", srv.Addr)
 		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
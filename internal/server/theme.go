@@ -0,0 +1,35 @@
+package server
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/ericstrs/site/internal/config"
+	"github.com/ericstrs/site/internal/render"
+	"github.com/ericstrs/site/internal/theme"
+)
+
+// loadTheme loads the configured theme and makes its templates, with
+// the templates in templatesDir layered over them, the ones pages are
+// rendered with. It returns the theme to serve static assets from.
+func loadTheme(cfg *config.Config, templatesDir string) (fs.FS, error) {
+	fsys, err := theme.Load(cfg.ThemesPath, cfg.Theme)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl, err := fs.Sub(fsys, "templates")
+	if err != nil {
+		return nil, err
+	}
+	if templatesDir != "" {
+		tmpl = theme.Overlay(os.DirFS(templatesDir), tmpl)
+	}
+
+	if err := render.LoadTemplates(tmpl); err != nil {
+		return nil, fmt.Errorf("failed to load templates: %w", err)
+	}
+
+	return fsys, nil
+}
@@ -0,0 +1,57 @@
+package sitemap
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strconv"
+	"time"
+)
+
+// ChangeFreqs are the change frequencies accepted by the sitemap
+// protocol.
+var ChangeFreqs = []string{"always", "hourly", "daily", "weekly", "monthly", "yearly", "never"}
+
+// URL is a single page of a sitemap.
+type URL struct {
+	Loc        string
+	LastMod    time.Time
+	ChangeFreq string
+	Priority   *float64 // between 0 and 1, nil leaves it unset
+}
+
+type urlSet struct {
+	XMLName xml.Name `xml:"urlset"`
+	XMLNS   string   `xml:"xmlns,attr"`
+	URLs    []url    `xml:"url"`
+}
+
+type url struct {
+	Loc        string `xml:"loc"`
+	LastMod    string `xml:"lastmod,omitempty"`
+	ChangeFreq string `xml:"changefreq,omitempty"`
+	Priority   string `xml:"priority,omitempty"`
+}
+
+// Sitemap returns the given pages encoded as a sitemap.
+func Sitemap(urls []URL) ([]byte, error) {
+	set := urlSet{XMLNS: "http://www.sitemaps.org/schemas/sitemap/0.9"}
+	for _, u := range urls {
+		v := url{Loc: u.Loc, ChangeFreq: u.ChangeFreq}
+		if !u.LastMod.IsZero() {
+			v.LastMod = u.LastMod.UTC().Format(time.RFC3339)
+		}
+		if u.Priority != nil {
+			v.Priority = strconv.FormatFloat(*u.Priority, 'f', -1, 64)
+		}
+		set.URLs = append(set.URLs, v)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+	if err := enc.Encode(set); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
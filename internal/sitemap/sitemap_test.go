@@ -0,0 +1,33 @@
+package sitemap
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSitemapPriority(t *testing.T) {
+	priority := func(p float64) *float64 { return &p }
+	tests := []struct {
+		priority *float64
+		want     string
+	}{
+		{nil, ""},
+		{priority(0), "<priority>0</priority>"},
+		{priority(0.25), "<priority>0.25</priority>"},
+		{priority(0.85), "<priority>0.85</priority>"},
+		{priority(1), "<priority>1</priority>"},
+	}
+	for _, tt := range tests {
+		out, err := Sitemap([]URL{{Loc: "https://example.com/", Priority: tt.priority}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := strings.Contains(string(out), "<priority>")
+		if tt.want == "" && got {
+			t.Errorf("unset priority written:\n%s", out)
+		}
+		if tt.want != "" && !strings.Contains(string(out), tt.want) {
+			t.Errorf("sitemap does not contain %s:\n%s", tt.want, out)
+		}
+	}
+}
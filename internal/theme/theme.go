@@ -0,0 +1,94 @@
+package theme
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/ericstrs/site/internal/render"
+)
+
+// DefaultName is the name of the theme embedded in the binary.
+const DefaultName = "default"
+
+// Default returns the embedded default theme. A theme holds a
+// "templates" directory and the static assets served from the site
+// root, such as "css/style.css".
+func Default() (fs.FS, error) {
+	return fs.Sub(render.Public, "public")
+}
+
+// Load returns the named theme. Files in the theme directory beneath
+// root override the embedded default theme one file at a time, so a
+// theme only needs to contain the files it changes. Only the default
+// theme may be missing from root.
+func Load(root, name string) (fs.FS, error) {
+	if name == "" {
+		name = DefaultName
+	}
+
+	def, err := Default()
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Join(root, name)
+	info, err := os.Stat(dir)
+	switch {
+	case errors.Is(err, fs.ErrNotExist) && name == DefaultName:
+		return def, nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to load theme %q: %w", name, err)
+	case !info.IsDir():
+		return nil, fmt.Errorf("failed to load theme %q: %s is not a directory", name, dir)
+	}
+
+	return Overlay(os.DirFS(dir), def), nil
+}
+
+// Overlay returns a file system that serves files from upper, falling
+// back to lower for files upper does not have. Directory listings
+// merge both.
+func Overlay(upper, lower fs.FS) fs.FS {
+	return overlay{upper: upper, lower: lower}
+}
+
+type overlay struct {
+	upper, lower fs.FS
+}
+
+func (o overlay) Open(name string) (fs.File, error) {
+	f, err := o.upper.Open(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return o.lower.Open(name)
+	}
+	return f, err
+}
+
+func (o overlay) ReadDir(name string) ([]fs.DirEntry, error) {
+	upper, uerr := fs.ReadDir(o.upper, name)
+	lower, lerr := fs.ReadDir(o.lower, name)
+	if uerr != nil && lerr != nil {
+		return nil, uerr
+	}
+
+	entries := make(map[string]fs.DirEntry)
+	for _, e := range lower {
+		entries[e.Name()] = e
+	}
+	for _, e := range upper {
+		entries[e.Name()] = e
+	}
+
+	merged := make([]fs.DirEntry, 0, len(entries))
+	for _, e := range entries {
+		merged = append(merged, e)
+	}
+	sort.Slice(merged, func(i, j int) bool {
+		return merged[i].Name() < merged[j].Name()
+	})
+	return merged, nil
+}
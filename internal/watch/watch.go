@@ -0,0 +1,80 @@
+package watch
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// snapshot maps every file path beneath the watched paths to its
+// modification time and size.
+type snapshot map[string]fileState
+
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// Poll calls onChange whenever a file beneath one of the given paths
+// is created, modified or removed, checking every interval until ctx
+// is done. Paths may be files or directories; missing paths are
+// watched for creation.
+func Poll(ctx context.Context, interval time.Duration, paths []string, onChange func()) {
+	prev := scan(paths)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			next := scan(paths)
+			if !next.equal(prev) {
+				onChange()
+			}
+			prev = next
+		}
+	}
+}
+
+// scan returns the current state of every file beneath paths.
+func scan(paths []string) snapshot {
+	s := make(snapshot)
+	for _, root := range paths {
+		filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+			if d.IsDir() {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return nil
+			}
+			s[path] = fileState{modTime: info.ModTime(), size: info.Size()}
+			return nil
+		})
+	}
+	return s
+}
+
+// equal reports whether s and other hold the same files and states.
+func (s snapshot) equal(other snapshot) bool {
+	if len(s) != len(other) {
+		return false
+	}
+	for path, state := range s {
+		if o, ok := other[path]; !ok || !o.modTime.Equal(state.modTime) || o.size != state.size {
+			return false
+		}
+	}
+	return true
+}